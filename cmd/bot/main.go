@@ -43,6 +43,10 @@ func main() {
 
 	defer db.CloseConnection()
 
+	if err = db.Migrate(); err != nil {
+		log.Fatalw("Migrate database", "error", err)
+	}
+
 	nc, err := broker.NewNatsBroker(c.NatsURL)
 	if err != nil {
 		log.Fatalw("NATS connection", "error", err)
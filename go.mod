@@ -5,12 +5,15 @@ go 1.22
 require (
 	github.com/botscubes/bot-components v0.0.0-20240618175944-a412323a3018
 	github.com/botscubes/user-service v0.2.0
+	github.com/davecgh/go-spew v1.1.1
 	github.com/goccy/go-json v0.10.2
 	github.com/gofiber/fiber/v2 v2.49.2
+	github.com/gofiber/websocket/v2 v2.2.1
 	github.com/jackc/pgx/v5 v5.4.3
 	github.com/mymmrac/telego v0.26.3
 	github.com/nats-io/nats.go v1.30.1
 	github.com/redis/go-redis/v9 v9.2.0
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
 	github.com/sethvargo/go-envconfig v1.0.0
 	go.uber.org/zap v1.26.0
 )
@@ -19,9 +22,9 @@ require (
 	github.com/andybalholm/brotli v1.0.5 // indirect
 	github.com/botscubes/bql v0.0.0-20240616210931-32c3a72e156c // indirect
 	github.com/cespare/xxhash/v2 v2.2.0 // indirect
-	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/fasthttp/router v1.4.20 // indirect
+	github.com/fasthttp/websocket v1.5.3 // indirect
 	github.com/golang-jwt/jwt/v5 v5.0.0 // indirect
 	github.com/golang/protobuf v1.5.3 // indirect
 	github.com/google/uuid v1.3.1 // indirect
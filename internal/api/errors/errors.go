@@ -53,6 +53,25 @@ var (
 	ErrValidation              = err.New(125, "Validation error")
 	ErrTargetComponentIdIsNull = err.New(126, "The output does not have a target component id")
 	ErrEmptyPath               = err.New(127, "Empty path")
+	ErrDeleteMainGroup         = err.New(128, "Main group cannot be deleted")
+	ErrGroupNotEmpty           = err.New(129, "The group contains components")
+	ErrGroupNameTooLong        = err.New(130, "Group name is too long")
+	ErrUnsupportedFlowFormat   = err.New(131, "Unsupported flow document format")
+	ErrFlowValidation          = err.New(132, "Bot flow validation failed")
+	ErrNoPublishedVersion      = err.New(133, "The bot has no published version")
+	ErrVersionNotFound         = err.New(134, "Version not found")
+	ErrVersionMessageTooLong   = err.New(135, "Version message is too long")
+	ErrNothingInJournal        = err.New(136, "There are no operations in the journal")
+	ErrCrossGroupConnections   = err.New(137, "Connections cannot cross group boundaries")
+	ErrGroupHasNoEntry         = err.New(138, "The group has no entry component")
+	ErrButtonNotFound          = err.New(139, "Button not found")
+	ErrSandboxSessionNotFound  = err.New(140, "Sandbox session not found")
+	ErrDebugSessionNotFound    = err.New(141, "Debug session not found")
+	ErrUserNotFound            = err.New(142, "User not found")
+	ErrJournalConflict         = err.New(143, "The components were changed after the operation")
+	ErrGroupCalled             = err.New(144, "The group is called by sub-flow components of other groups")
+	ErrInvalidSandboxTicket    = err.New(145, "Invalid or expired sandbox ticket")
+	ErrDebugSessionBusy        = err.New(146, "The debug session is running another command")
 )
 
 func InvalidParam(mes string) *err.ServiceError {
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/botscubes/bot-service/internal/graph"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *ApiHandler) GetGroupAnalysis(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	groupId, ok := ctx.Locals("groupId").(int64)
+	if !ok {
+		h.log.Errorw("GroupId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	components, err := h.db.GetComponents(botId, groupId)
+	if err != nil {
+		h.log.Errorw("failed get bot components for analysis", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	entryId, err := h.db.GetGroupEntry(botId, groupId)
+	if err != nil {
+		h.log.Errorw("failed get group entry", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	g := graph.New(components)
+	if entryId != nil {
+		g.EntryId = *entryId
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(g.Analyze())
+}
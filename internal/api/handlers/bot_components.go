@@ -1,17 +1,26 @@
 package handlers
 
 import (
+	"context"
+
 	"github.com/botscubes/bot-components/components"
 	"github.com/botscubes/bot-service/internal/model"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 
 	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
 )
 
 type AddComponentRes struct {
 	Id int64 `json:"id"`
 }
 
+type dataValidationRes struct {
+	*se.ServiceError
+	Errors []*model.DataError `json:"errors"`
+}
+
 func (h *ApiHandler) GetBotComponents(ctx *fiber.Ctx) error {
 	botId, ok := ctx.Locals("botId").(int64)
 	if !ok {
@@ -34,6 +43,11 @@ func (h *ApiHandler) GetBotComponents(ctx *fiber.Ctx) error {
 }
 
 func (h *ApiHandler) AddComponent(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
 	botId, ok := ctx.Locals("botId").(int64)
 	if !ok {
 		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
@@ -54,20 +68,27 @@ func (h *ApiHandler) AddComponent(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
 	}
 
-	compId, err := h.db.AddComponent(botId, groupId, &model.Component{
-		Position: reqData.Position,
-		ComponentData: components.ComponentData{
-			ComponentTypeData: components.ComponentTypeData{
-				Type: reqData.Type,
+	var compId int64
+	before, after, err := h.db.RunEditorOp(botId, nil, false, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		id, err := h.db.AddComponentTx(c, tx, botId, groupId, &model.Component{
+			Position: reqData.Position,
+			ComponentData: components.ComponentData{
+				ComponentTypeData: components.ComponentTypeData{
+					Type: reqData.Type,
+				},
+				Path: reqData.Type,
 			},
-			Path: reqData.Type,
-		},
+		})
+		compId = id
+		return []int64{id}, err
 	})
 	if err != nil {
 		h.log.Errorw("failed add component", "error", err)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 
+	h.journal(botId, userId, opAddComponent, before, after)
+
 	dataRes := &AddComponentRes{
 		Id: compId,
 	}
@@ -75,6 +96,11 @@ func (h *ApiHandler) AddComponent(ctx *fiber.Ctx) error {
 }
 
 func (h *ApiHandler) DeleteComponent(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
 	botId, ok := ctx.Locals("botId").(int64)
 	if !ok {
 		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
@@ -95,16 +121,79 @@ func (h *ApiHandler) DeleteComponent(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrDeleteStartComponent)
 	}
 
-	err := h.db.DeleteComponent(botId, groupId, componentId)
+	// connections of the neighbours are removed along with the component
+	ids := []int64{componentId}
+	before, after, err := h.db.RunEditorOp(botId, ids, true, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.DeleteComponentsTx(c, tx, botId, groupId, ids)
+	})
 	if err != nil {
 		h.log.Errorw("failed delete component", "error", err)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 
+	h.journal(botId, userId, opDeleteComponent, before, after)
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Delete a set of components in one transaction
+func (h *ApiHandler) DeleteComponents(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	groupId, ok := ctx.Locals("groupId").(int64)
+	if !ok {
+		h.log.Errorw("GroupId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.DelSetComponentsReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	componentsExist, err := h.db.CheckComponentsExist(botId, groupId, *reqData.Data)
+	if err != nil {
+		h.log.Errorw("failed check components exist", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !componentsExist {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrComponentNotFound)
+	}
+
+	// connections of the neighbours are removed along with the components
+	ids := *reqData.Data
+	before, after, err := h.db.RunEditorOp(botId, ids, true, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.DeleteComponentsTx(c, tx, botId, groupId, ids)
+	})
+	if err != nil {
+		h.log.Errorw("failed delete components", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	h.journal(botId, userId, opDeleteComponents, before, after)
+
 	return ctx.SendStatus(fiber.StatusNoContent)
 }
 
 func (h *ApiHandler) SetComponentPosition(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
 	botId, ok := ctx.Locals("botId").(int64)
 	if !ok {
 		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
@@ -130,15 +219,26 @@ func (h *ApiHandler) SetComponentPosition(ctx *fiber.Ctx) error {
 	if errValidate := position.Validate(); errValidate != nil {
 		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
 	}
-	err := h.db.SetComponentPosition(botId, groupId, componentId, position)
+
+	before, after, err := h.db.RunEditorOp(botId, []int64{componentId}, false, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.SetComponentPositionTx(c, tx, botId, groupId, componentId, position)
+	})
 	if err != nil {
 		h.log.Errorw("failed set component position", "error", err)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
+
+	h.journal(botId, userId, opSetComponentPosition, before, after)
+
 	return ctx.SendStatus(fiber.StatusNoContent)
 }
 
 func (h *ApiHandler) UpdateComponentData(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
 	botId, ok := ctx.Locals("botId").(int64)
 	if !ok {
 		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
@@ -167,20 +267,69 @@ func (h *ApiHandler) UpdateComponentData(ctx *fiber.Ctx) error {
 		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
 
-	if errValidate := model.ValidateSpecificComponentData(componentType, *data); errValidate != nil {
-		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	if errs := model.ValidateComponentData(componentType, *data, false); len(errs) > 0 {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(&dataValidationRes{
+			ServiceError: e.ErrValidation,
+			Errors:       errs,
+		})
 	}
 
-	if err = h.db.UpdateComponentData(botId, groupId, componentId, *data); err != nil {
+	if componentType == model.TypeSubflow {
+		if groupId, ok := (*data)["groupId"].(float64); ok {
+			errValidate, err := h.checkSubflowGroup(botId, int64(groupId))
+			if err != nil {
+				h.log.Errorw("failed check sub-flow group", "error", err)
+				return ctx.SendStatus(fiber.StatusInternalServerError)
+			}
 
+			if errValidate != nil {
+				return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+			}
+		}
+	}
+
+	before, after, err := h.db.RunEditorOp(botId, []int64{componentId}, false, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.UpdateComponentDataTx(c, tx, botId, groupId, componentId, *data)
+	})
+	if err != nil {
 		h.log.Errorw("failed set component data", "error", err)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 
-	return ctx.SendStatus(fiber.StatusNoContent)
+	h.journal(botId, userId, opUpdateComponentData, before, after)
+
+	return h.savedComponentRes(ctx, botId, groupId, componentId)
+}
+
+// The group called by a sub-flow component must exist and have an entry component
+func (h *ApiHandler) checkSubflowGroup(botId int64, groupId int64) (*se.ServiceError, error) {
+	existGroup, err := h.db.CheckGroupExist(botId, groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !existGroup {
+		return e.ErrGroupNotFound, nil
+	}
+
+	entryId, err := h.db.GetGroupEntry(botId, groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	if entryId == nil {
+		return e.ErrGroupHasNoEntry, nil
+	}
+
+	return nil, nil
 }
 
 func (h *ApiHandler) UpdateComponentPath(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
 	botId, ok := ctx.Locals("botId").(int64)
 	if !ok {
 		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
@@ -206,11 +355,24 @@ func (h *ApiHandler) UpdateComponentPath(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrEmptyPath)
 	}
 
-	if err := h.db.UpdateComponentPath(botId, groupId, componentId, path); err != nil {
-
+	before, after, err := h.db.RunEditorOp(botId, []int64{componentId}, false, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.UpdateComponentPathTx(c, tx, botId, groupId, componentId, path)
+	})
+	if err != nil {
 		h.log.Errorw("failed set component path", "error", err)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 
-	return ctx.SendStatus(fiber.StatusNoContent)
+	h.journal(botId, userId, opUpdateComponentPath, before, after)
+
+	// readers of the old and the new variable in the group are affected too
+	variables := []string{}
+	if len(before) == 1 && before[0].Component != nil {
+		c := *before[0].Component
+		variables = append(variables, c.VariableWrite())
+		c.Path = path
+		variables = append(variables, c.VariableWrite())
+	}
+
+	return h.savedComponentRes(ctx, botId, groupId, componentId, variables...)
 }
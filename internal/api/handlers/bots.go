@@ -5,10 +5,18 @@ import (
 	e "github.com/botscubes/bot-service/internal/api/errors"
 	"github.com/botscubes/bot-service/internal/bot"
 	"github.com/botscubes/bot-service/internal/config"
+	"github.com/botscubes/bot-service/internal/graph"
 	"github.com/botscubes/bot-service/internal/model"
 	"github.com/gofiber/fiber/v2"
+
+	se "github.com/botscubes/user-service/pkg/service_error"
 )
 
+type flowValidationRes struct {
+	*se.ServiceError
+	Issues []*graph.Issue `json:"issues"`
+}
+
 type newBotRes struct {
 	BotId     int64            `json:"botId"`
 	Component *model.Component `json:"component"`
@@ -114,6 +122,9 @@ func (h *ApiHandler) DeleteBot(ctx *fiber.Ctx) error {
 	return ctx.SendStatus(fiber.StatusNoContent)
 }
 
+// Message of the version published on the first start of the bot
+const firstStartVersionMessage = "Published on the first start"
+
 func (h *ApiHandler) StartBot(ctx *fiber.Ctx) error {
 
 	userId, ok := ctx.Locals("userId").(int64)
@@ -137,6 +148,48 @@ func (h *ApiHandler) StartBot(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrBotAlreadyRunning)
 	}
 
+	// the bot executes the current version, by default the last published one
+	currentVersion, err := h.db.GetBotVersion(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot version", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	var version int64
+	if currentVersion != nil {
+		version = *currentVersion
+	} else if version, err = h.db.GetLastVersionNumber(botId); err != nil {
+		h.log.Errorw("failed get last version number", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	// the bot without published versions (e.g. created before the versions) is started
+	// with the draft, it is published as the first version after the checks
+	var flow *model.Flow
+	if version == 0 {
+		if flow, err = h.db.GetFlow(botId); err != nil {
+			h.log.Errorw("failed get bot flow (start)", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	// pre-flight validation of the flow, can be skipped with ?force=true
+	if !ctx.QueryBool("force") {
+		if flow == nil {
+			if flow, err = h.db.GetVersionFlow(botId, version); err != nil {
+				h.log.Errorw("failed get version flow (start validation)", "error", err)
+				return ctx.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		if issues := graph.ValidateFlow(flow); len(issues) > 0 {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(&flowValidationRes{
+				ServiceError: e.ErrFlowValidation,
+				Issues:       issues,
+			})
+		}
+	}
+
 	token, err := h.db.GetBotToken(userId, botId)
 	if err != nil {
 		h.log.Errorw("failed get bot token", "error", err)
@@ -158,8 +211,16 @@ func (h *ApiHandler) StartBot(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrInvalidToken)
 	}
 
+	if version == 0 {
+		message := firstStartVersionMessage
+		if version, err = h.db.AddVersion(botId, userId, &message, flow); err != nil {
+			h.log.Errorw("failed add version (start)", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
 	// starting worker
-	if err := h.mb.StartBot(botId, *token); err != nil {
+	if err := h.mb.StartBot(botId, *token, version); err != nil {
 		h.log.Errorw("failed broker: start bot", "error", err)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
@@ -180,6 +241,11 @@ func (h *ApiHandler) StartBot(ctx *fiber.Ctx) error {
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 
+	if err = h.db.SetBotVersion(botId, version); err != nil {
+		h.log.Errorw("failed update bot version", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
 	return ctx.SendStatus(fiber.StatusNoContent)
 }
 
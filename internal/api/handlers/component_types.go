@@ -0,0 +1,11 @@
+package handlers
+
+import (
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Registry of the component types for the editor forms
+func (h *ApiHandler) GetComponentTypes(ctx *fiber.Ctx) error {
+	return ctx.Status(fiber.StatusOK).JSON(model.ComponentTypes)
+}
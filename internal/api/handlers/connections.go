@@ -1,13 +1,22 @@
 package handlers
 
 import (
+	"context"
+	"slices"
+
 	"github.com/botscubes/bot-service/internal/model"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 
 	e "github.com/botscubes/bot-service/internal/api/errors"
 )
 
 func (h *ApiHandler) AddConnetion(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
 	botId, ok := ctx.Locals("botId").(int64)
 	if !ok {
 		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
@@ -52,17 +61,42 @@ func (h *ApiHandler) AddConnetion(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
 	}
 
-	err = h.db.AddConnection(botId, groupId, reqData)
+	// a numeric output belongs to the button with the same index
+	info := model.GetComponentTypeInfo(componentType)
+	if info.NumericOutputs && !slices.Contains(info.Outputs, *reqData.SourcePointName) {
+		data, err := h.db.GetComponentData(botId, groupId, *reqData.SourceComponentId)
+		if err != nil {
+			h.log.Errorw("failed get component data", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		if !model.HasButton(data, *reqData.SourcePointName) {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrButtonNotFound)
+		}
+	}
+
+	// the previous target of the output is linked with the source
+	ids := []int64{*reqData.SourceComponentId, *reqData.TargetComponentId}
+	before, after, err := h.db.RunEditorOp(botId, ids, true, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.AddConnectionTx(c, tx, botId, groupId, reqData)
+	})
 	if err != nil {
 		h.log.Errorw("failed add connection", "error", err)
 
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 
+	h.journal(botId, userId, opAddConnection, before, after)
+
 	return ctx.SendStatus(fiber.StatusCreated)
 }
 
 func (h *ApiHandler) DeleteConnection(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
 	botId, ok := ctx.Locals("botId").(int64)
 	if !ok {
 		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
@@ -109,12 +143,17 @@ func (h *ApiHandler) DeleteConnection(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrTargetComponentIdIsNull)
 	}
 
-	err = h.db.DeleteConnection(botId, groupId, reqData, *targetComponentId)
+	ids := []int64{*reqData.SourceComponentId, *targetComponentId}
+	before, after, err := h.db.RunEditorOp(botId, ids, false, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.DeleteConnectionTx(c, tx, botId, groupId, reqData, *targetComponentId)
+	})
 	if err != nil {
 		h.log.Errorw("failed delete connection", "error", err)
 		return ctx.SendStatus(fiber.StatusInternalServerError)
 	}
 
+	h.journal(botId, userId, opDeleteConnection, before, after)
+
 	return ctx.SendStatus(fiber.StatusNoContent)
 
 }
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+)
+
+type copyComponentsRes struct {
+	Ids map[int64]int64 `json:"ids"`
+}
+
+// Copy a selection of components (possibly of another bot of the user) into the group
+func (h *ApiHandler) CopyComponents(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	groupId, ok := ctx.Locals("groupId").(int64)
+	if !ok {
+		h.log.Errorw("GroupId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.CopyComponentsReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	sourceBotId := botId
+	if reqData.SourceBotId != nil && *reqData.SourceBotId != botId {
+		sourceBotId = *reqData.SourceBotId
+
+		existBot, err := h.db.CheckBotExist(userId, sourceBotId)
+		if err != nil {
+			h.log.Errorw("failed check bot exist", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		if !existBot {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrBotNotFound)
+		}
+	}
+
+	states, err := h.db.GetComponentStates(sourceBotId, *reqData.Ids)
+	if err != nil {
+		h.log.Errorw("failed get components for copy", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	offset := reqData.Offset
+	if offset == nil {
+		offset = &model.Point{X: model.DefaultCopyOffset, Y: model.DefaultCopyOffset}
+	}
+
+	comps := make([]*model.Component, 0, len(states))
+	for _, s := range states {
+		if s.Component == nil {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrComponentNotFound)
+		}
+
+		c := s.Component
+		// groups of another bot cannot be called
+		if sourceBotId != botId {
+			c.RemapSubflowGroupId(nil)
+		}
+
+		if c.Position != nil {
+			c.Position.X += offset.X
+			c.Position.Y += offset.Y
+		}
+		comps = append(comps, c)
+	}
+
+	var ids map[int64]int64
+	before, after, err := h.db.RunEditorOp(botId, nil, false, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		copied, err := h.db.CopyComponentsTx(c, tx, botId, groupId, comps)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = copied
+		created := make([]int64, 0, len(copied))
+		for _, id := range copied {
+			created = append(created, id)
+		}
+		return created, nil
+	})
+	if err != nil {
+		h.log.Errorw("failed copy components", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	h.journal(botId, userId, opCopyComponents, before, after)
+
+	return ctx.Status(fiber.StatusCreated).JSON(&copyComponentsRes{Ids: ids})
+}
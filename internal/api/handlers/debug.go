@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/botscubes/bot-service/internal/simulator"
+	"github.com/gofiber/fiber/v2"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+)
+
+type debugSessionRes struct {
+	SessionId string `json:"sessionId"`
+	*simulator.DebugState
+}
+
+func (h *ApiHandler) SetComponentBreakpoint(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	groupId, ok := ctx.Locals("groupId").(int64)
+	if !ok {
+		h.log.Errorw("GroupId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	componentId, ok := ctx.Locals("componentId").(int64)
+	if !ok {
+		h.log.Errorw("ComponentId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.SetBreakpointReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	if err := h.db.SetComponentBreakpoint(botId, groupId, componentId, *reqData.Breakpoint); err != nil {
+		h.log.Errorw("failed set component breakpoint", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Start debugging of the current (not published) flow of the bot, the flow runs
+// until a breakpoint or an input of the user that is not in the queue
+func (h *ApiHandler) NewDebugSession(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.NewDebugSessionReq)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(reqData); err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	flow, err := h.db.GetFlow(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot flow (debug)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	d := simulator.NewDebugger(flow, reqData.MockResults())
+	if reqData.Variables != nil {
+		if err := d.SetVariables(reqData.Variables); err != nil {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.InvalidParam("variables"))
+		}
+	}
+	d.AddInputs(reqData.Inputs)
+
+	if err := h.setBreakpoints(botId, d); err != nil {
+		h.log.Errorw("failed get breakpoints", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	sessionId, err := newSessionId()
+	if err != nil {
+		h.log.Errorw("failed generate debug session id", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	state := d.Continue()
+	if err := h.r.AddDebugSession(botId, d.Session(sessionId)); err != nil {
+		h.log.Errorw("failed add debug session", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(&debugSessionRes{
+		SessionId:  sessionId,
+		DebugState: state,
+	})
+}
+
+// Run until the next breakpoint
+func (h *ApiHandler) ContinueDebugSession(ctx *fiber.Ctx) error {
+	return h.debugCommand(ctx, (*simulator.Debugger).Continue)
+}
+
+// Execute one component
+func (h *ApiHandler) StepDebugSession(ctx *fiber.Ctx) error {
+	return h.debugCommand(ctx, (*simulator.Debugger).Step)
+}
+
+func (h *ApiHandler) DeleteDebugSession(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	deleted, err := h.r.DelDebugSession(botId, ctx.Params("sessionId"))
+	if err != nil {
+		h.log.Errorw("failed delete debug session", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !deleted {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrDebugSessionNotFound)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ApiHandler) debugCommand(ctx *fiber.Ctx, command func(*simulator.Debugger) *simulator.DebugState) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.DebugCommandReq)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(reqData); err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	// the session is saved in redis, the commands may come to any instance of the service
+	sessionId := ctx.Params("sessionId")
+	locked, err := h.r.LockDebugSession(botId, sessionId)
+	if err != nil {
+		h.log.Errorw("failed lock debug session", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !locked {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrDebugSessionBusy)
+	}
+
+	defer func() {
+		if err := h.r.UnlockDebugSession(botId, sessionId); err != nil {
+			h.log.Errorw("failed unlock debug session", "error", err)
+		}
+	}()
+
+	session, err := h.r.GetDebugSession(botId, sessionId)
+	if err != nil {
+		h.log.Errorw("failed get debug session", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if session == nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrDebugSessionNotFound)
+	}
+
+	d, err := simulator.RestoreDebugger(session)
+	if err != nil {
+		h.log.Errorw("failed restore debugger", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !d.AddInputs(reqData.Inputs) {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(
+			e.InvalidParam("inputs: no more than " + strconv.Itoa(model.MaxSimulationInputs) + " queued inputs"),
+		)
+	}
+
+	// the user may change the breakpoints during the session
+	if err := h.setBreakpoints(botId, d); err != nil {
+		h.log.Errorw("failed get breakpoints", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	state := command(d)
+
+	// the session could be deleted while the command was running
+	saved, err := h.r.SetDebugSession(botId, d.Session(sessionId))
+	if err != nil {
+		h.log.Errorw("failed save debug session", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !saved {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrDebugSessionNotFound)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(&debugSessionRes{
+		SessionId:  sessionId,
+		DebugState: state,
+	})
+}
+
+func (h *ApiHandler) setBreakpoints(botId int64, d *simulator.Debugger) error {
+	ids, err := h.db.GetBreakpoints(botId)
+	if err != nil {
+		return err
+	}
+
+	d.SetBreakpoints(ids)
+	return nil
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Syntax check of the expression for the editor
+func (h *ApiHandler) CheckExpression(ctx *fiber.Ctx) error {
+	reqData := new(model.CheckExpressionReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(&model.CheckExpressionRes{
+		Errors: model.CheckExpression(*reqData.Syntax, *reqData.Expression),
+	})
+}
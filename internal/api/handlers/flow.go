@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+)
+
+func (h *ApiHandler) ExportBot(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	flow, err := h.db.GetFlow(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot flow (export)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(flow)
+}
+
+type importBotRes struct {
+	BotId int64 `json:"botId"`
+}
+
+// Create a new bot from the flow document
+func (h *ApiHandler) ImportBot(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	flow := new(model.Flow)
+	if err := ctx.BodyParser(flow); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := flow.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	token := ""
+	botId, err := h.db.CreateBotFromFlow(&model.Bot{
+		UserId: userId,
+		Token:  &token,
+		Title:  flow.Title,
+		Status: model.StatusBotStopped,
+	}, flow)
+	if err != nil {
+		h.log.Errorw("failed create bot from flow (import)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(&importBotRes{
+		BotId: botId,
+	})
+}
+
+// Replace groups and components of a stopped bot with the flow document
+func (h *ApiHandler) ImportBotFlow(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	flow := new(model.Flow)
+	if err := ctx.BodyParser(flow); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := flow.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	botStatus, err := h.db.GetBotStatus(botId, userId)
+	if err != nil {
+		h.log.Errorw("failed get bot status", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if botStatus == model.StatusBotRunning {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrBotNeedsStopped)
+	}
+
+	if err := h.db.ReplaceFlow(botId, flow); err != nil {
+		h.log.Errorw("failed replace bot flow (import)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+const cloneTitleSuffix = " (copy)"
+
+// Title of the bot clone: the source title with the suffix, cut to the max title length
+func cloneTitle(title *string) *string {
+	t := cloneTitleSuffix
+	if title != nil {
+		t = *title + cloneTitleSuffix
+	}
+
+	if runes := []rune(t); len(runes) > model.MaxTitleLen {
+		t = string(runes[:model.MaxTitleLen])
+	}
+
+	return &t
+}
+
+// Create a new bot with a copy of all groups and components of the bot
+func (h *ApiHandler) CloneBot(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	// the title is optional
+	data := new(model.NewBotReq)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(data); err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		if data.Title != nil {
+			if err := data.Validate(); err != nil {
+				return ctx.Status(fiber.StatusUnprocessableEntity).JSON(err)
+			}
+		}
+	}
+
+	flow, err := h.db.GetFlow(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot flow (clone)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if data.Title != nil {
+		flow.Title = data.Title
+	} else {
+		flow.Title = cloneTitle(flow.Title)
+	}
+
+	// the draft is checked as an imported flow, the data saved before the stricter
+	// rules must be fixed first
+	if errValidate := flow.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	// the published versions are not copied: the components of the clone get new ids
+	// and the versions of the source would not match its draft
+	token := ""
+	newBotId, err := h.db.CreateBotFromFlow(&model.Bot{
+		UserId: userId,
+		Token:  &token,
+		Title:  flow.Title,
+		Status: model.StatusBotStopped,
+	}, flow)
+	if err != nil {
+		h.log.Errorw("failed create bot from flow (clone)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(&importBotRes{
+		BotId: newBotId,
+	})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+type AddGroupRes struct {
+	Id int64 `json:"id"`
+}
+
+type groupCalledRes struct {
+	*se.ServiceError
+	Components []int64 `json:"components"`
+}
+
+func (h *ApiHandler) GetGroups(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	groups, err := h.db.GetGroups(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot groups", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(groups)
+}
+
+func (h *ApiHandler) AddGroup(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.AddGroupReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	groupId, err := h.db.AddGroup(botId, *reqData.Name)
+	if err != nil {
+		h.log.Errorw("failed add group", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(&AddGroupRes{
+		Id: groupId,
+	})
+}
+
+func (h *ApiHandler) UpdateGroup(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	groupId, ok := ctx.Locals("groupId").(int64)
+	if !ok {
+		h.log.Errorw("GroupId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.UpdGroupReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	if reqData.EntryComponentId != nil {
+		componentExist, err := h.db.CheckComponentExist(botId, groupId, *reqData.EntryComponentId)
+		if err != nil {
+			h.log.Errorw("failed check component exist", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		if !componentExist {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrComponentNotFound)
+		}
+	}
+
+	if reqData.Name != nil {
+		if err := h.db.SetGroupName(botId, groupId, *reqData.Name); err != nil {
+			h.log.Errorw("failed set group name", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	if reqData.EntryComponentId != nil {
+		if err := h.db.SetGroupEntry(botId, groupId, *reqData.EntryComponentId); err != nil {
+			h.log.Errorw("failed set group entry", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Delete group. A group that still holds components is deleted
+// only with ?cascade=true, the components are deleted along with it.
+func (h *ApiHandler) DeleteGroup(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	groupId, ok := ctx.Locals("groupId").(int64)
+	if !ok {
+		h.log.Errorw("GroupId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	mainGroupId, err := h.db.GetMainGroupId(botId)
+	if err != nil {
+		h.log.Errorw("failed get main group id", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if groupId == mainGroupId {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrDeleteMainGroup)
+	}
+
+	if !ctx.QueryBool("cascade") {
+		count, err := h.db.GetCountComponentsInGroup(botId, groupId)
+		if err != nil {
+			h.log.Errorw("failed get count components in group", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		if count > 0 {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrGroupNotEmpty)
+		}
+	}
+
+	// the sub-flow calls of the deleted group would lead nowhere, also with the cascade
+	callers, err := h.db.GetSubflowCallers(botId, groupId)
+	if err != nil {
+		h.log.Errorw("failed get subflow callers", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if len(callers) > 0 {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(&groupCalledRes{
+			ServiceError: e.ErrGroupCalled,
+			Components:   callers,
+		})
+	}
+
+	if err := h.db.DeleteGroup(botId, groupId); err != nil {
+		h.log.Errorw("failed delete group", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/botscubes/bot-service/internal/database/pgsql"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+const (
+	opAddComponent          = "addComponent"
+	opDeleteComponent       = "deleteComponent"
+	opDeleteComponents      = "deleteComponents"
+	opSetComponentPosition  = "setComponentPosition"
+	opSetComponentPositions = "setComponentPositions"
+	opMoveComponents        = "moveComponents"
+	opCopyComponents        = "copyComponents"
+	opLayoutComponents      = "layoutComponents"
+	opUpdateComponentData   = "updateComponentData"
+	opUpdateComponentPath   = "updateComponentPath"
+	opAddConnection         = "addConnection"
+	opDeleteConnection      = "deleteConnection"
+
+	journalUndo = "undo"
+	journalRedo = "redo"
+)
+
+// Add the operation to the user journal, the states are read in the transaction of the
+// operation (pgsql.RunEditorOp). An operation that changed nothing is not journaled.
+// The journal is best effort: errors are logged and the operation is not journaled.
+func (h *ApiHandler) journal(
+	botId int64, userId int64, operation string, before []*model.ComponentState, after []*model.ComponentState,
+) {
+	if len(before) == 0 {
+		return
+	}
+
+	if err := h.r.AddJournalEntry(botId, userId, &model.JournalEntry{
+		Operation: operation,
+		Before:    before,
+		After:     after,
+	}); err != nil {
+		h.log.Errorw("failed add journal entry", "error", err)
+	}
+}
+
+type journalRes struct {
+	Operation  string                  `json:"operation"`
+	Components []*model.ComponentState `json:"components"`
+}
+
+type journalConflictRes struct {
+	*se.ServiceError
+	Operation  string  `json:"operation"`
+	Components []int64 `json:"components"`
+}
+
+func (h *ApiHandler) Undo(ctx *fiber.Ctx) error {
+	return h.applyJournal(ctx, journalUndo)
+}
+
+func (h *ApiHandler) Redo(ctx *fiber.Ctx) error {
+	return h.applyJournal(ctx, journalRedo)
+}
+
+// Undo or redo the last operation of the user
+func (h *ApiHandler) applyJournal(ctx *fiber.Ctx, direction string) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	pop, push, restore := h.r.PopUndoEntry, h.r.PushRedoEntry, h.r.PushUndoEntry
+	if direction == journalRedo {
+		pop, push, restore = h.r.PopRedoEntry, h.r.PushUndoEntry, h.r.PushRedoEntry
+	}
+
+	entry, err := pop(botId, userId)
+	if err != nil {
+		h.log.Errorw("failed pop journal entry", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if entry == nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrNothingInJournal)
+	}
+
+	// the entry is discarded on request without applying it
+	if ctx.QueryBool("discard") {
+		return ctx.Status(fiber.StatusOK).JSON(&journalRes{
+			Operation:  entry.Operation,
+			Components: []*model.ComponentState{},
+		})
+	}
+
+	// the entry that is not applied stays in the journal
+	restoreEntry := func() {
+		if err := restore(botId, userId, entry); err != nil {
+			h.log.Errorw("failed restore journal entry", "error", err)
+		}
+	}
+
+	expected, states := entry.After, entry.Before
+	if direction == journalRedo {
+		expected, states = entry.Before, entry.After
+	}
+
+	if err := h.db.SetComponentStates(botId, expected, states); err != nil {
+		restoreEntry()
+
+		var statesErr *pgsql.ComponentStatesError
+		if errors.As(err, &statesErr) {
+			serviceErr := e.ErrJournalConflict
+			if errors.Is(err, pgsql.ErrComponentGroupsDeleted) {
+				serviceErr = e.ErrGroupNotFound
+			}
+
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(&journalConflictRes{
+				ServiceError: serviceErr,
+				Operation:    entry.Operation,
+				Components:   statesErr.Ids,
+			})
+		}
+
+		h.log.Errorw("failed set component states ("+direction+")", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if err := push(botId, userId, entry); err != nil {
+		h.log.Errorw("failed push journal entry", "error", err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(&journalRes{
+		Operation:  entry.Operation,
+		Components: states,
+	})
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+
+	"github.com/botscubes/bot-service/internal/graph"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+type crossingConnectionsRes struct {
+	*se.ServiceError
+	Connections []*graph.ConnectionRef `json:"connections"`
+}
+
+type moveComponentsRes struct {
+	BrokenConnections []*graph.ConnectionRef `json:"brokenConnections"`
+}
+
+// Move a selection of components to another group
+func (h *ApiHandler) MoveComponents(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	groupId, ok := ctx.Locals("groupId").(int64)
+	if !ok {
+		h.log.Errorw("GroupId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.MoveComponentsReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	if *reqData.GroupId == groupId {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.InvalidParam("groupId"))
+	}
+
+	existGroup, err := h.db.CheckGroupExist(botId, *reqData.GroupId)
+	if err != nil {
+		h.log.Errorw("failed check group exist", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !existGroup {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrGroupNotFound)
+	}
+
+	componentsExist, err := h.db.CheckComponentsExist(botId, groupId, *reqData.Ids)
+	if err != nil {
+		h.log.Errorw("failed check components exist", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !componentsExist {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrComponentNotFound)
+	}
+
+	components, err := h.db.GetComponents(botId, groupId)
+	if err != nil {
+		h.log.Errorw("failed get bot components for move", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	crossing := graph.New(components).CrossingConnections(*reqData.Ids)
+	if len(crossing) > 0 && !reqData.BreakConnections {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(&crossingConnectionsRes{
+			ServiceError: e.ErrCrossGroupConnections,
+			Connections:  crossing,
+		})
+	}
+
+	// the entry of the group is reset when it is moved, the sub-flow calls of the group
+	// would lead nowhere
+	entryId, err := h.db.GetGroupEntry(botId, groupId)
+	if err != nil {
+		h.log.Errorw("failed get group entry", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if entryId != nil && slices.Contains(*reqData.Ids, *entryId) && !reqData.ResetEntry {
+		callers, err := h.db.GetSubflowCallers(botId, groupId)
+		if err != nil {
+			h.log.Errorw("failed get subflow callers", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		if len(callers) > 0 {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(&groupCalledRes{
+				ServiceError: e.ErrGroupCalled,
+				Components:   callers,
+			})
+		}
+	}
+
+	// the broken connections change the remaining components linked with the moved ones
+	before, after, err := h.db.RunEditorOp(botId, *reqData.Ids, reqData.BreakConnections, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.MoveComponentsTx(c, tx, botId, groupId, *reqData.GroupId, *reqData.Ids, reqData.BreakConnections)
+	})
+	if err != nil {
+		h.log.Errorw("failed move components", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	h.journal(botId, userId, opMoveComponents, before, after)
+
+	return ctx.Status(fiber.StatusOK).JSON(&moveComponentsRes{
+		BrokenConnections: crossing,
+	})
+}
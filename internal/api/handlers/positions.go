@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/botscubes/bot-service/internal/graph"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+)
+
+// Set positions of several components (multi-component drag in the editor)
+// or arrange all components of the group with the layout flag
+func (h *ApiHandler) SetComponentPositions(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	groupId, ok := ctx.Locals("groupId").(int64)
+	if !ok {
+		h.log.Errorw("GroupId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.SetPositionsReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	if reqData.Layout {
+		return h.layoutComponents(ctx, userId, botId, groupId)
+	}
+
+	ids := reqData.Ids()
+	componentsExist, err := h.db.CheckComponentsExist(botId, groupId, ids)
+	if err != nil {
+		h.log.Errorw("failed check components exist", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !componentsExist {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrComponentNotFound)
+	}
+
+	before, after, err := h.db.RunEditorOp(botId, ids, false, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.SetComponentPositionsTx(c, tx, botId, groupId, *reqData.Data)
+	})
+	if err != nil {
+		h.log.Errorw("failed set component positions", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	h.journal(botId, userId, opSetComponentPositions, before, after)
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Arrange the components of the group in layers along the outputs
+func (h *ApiHandler) layoutComponents(ctx *fiber.Ctx, userId int64, botId int64, groupId int64) error {
+	components, err := h.db.GetComponents(botId, groupId)
+	if err != nil {
+		h.log.Errorw("failed get bot components for layout", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	entryId, err := h.db.GetGroupEntry(botId, groupId)
+	if err != nil {
+		h.log.Errorw("failed get group entry", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	g := graph.New(components)
+	if entryId != nil {
+		g.EntryId = *entryId
+	}
+
+	positions := g.Layout()
+	if len(positions) == 0 {
+		return ctx.Status(fiber.StatusOK).JSON(positions)
+	}
+
+	before, after, err := h.db.RunEditorOp(botId, g.Ids(), false, func(c context.Context, tx pgx.Tx) ([]int64, error) {
+		return nil, h.db.SetComponentPositionsTx(c, tx, botId, groupId, positions)
+	})
+	if err != nil {
+		h.log.Errorw("failed set component positions", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	h.journal(botId, userId, opLayoutComponents, before, after)
+
+	return ctx.Status(fiber.StatusOK).JSON(positions)
+}
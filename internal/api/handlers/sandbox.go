@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/botscubes/bot-service/internal/simulator"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+// Event pushed to the client of the sandbox session: the result of the input or an error
+type sandboxEvent struct {
+	Result *simulator.Result `json:"result,omitempty"`
+	Error  *se.ServiceError  `json:"error,omitempty"`
+}
+
+// Start the test chat with the current (not published) flow of the bot
+func (h *ApiHandler) NewSandboxSession(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.NewSandboxSessionReq)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(reqData); err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	state, err := simulator.NewState(reqData.Variables)
+	if err != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.InvalidParam("variables"))
+	}
+
+	sessionId, err := newSessionId()
+	if err != nil {
+		h.log.Errorw("failed generate sandbox session id", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	session := &model.SandboxSession{
+		Id:    sessionId,
+		State: state,
+		Mocks: reqData.MockResults(),
+	}
+
+	if err := h.r.SetSandboxSession(botId, session); err != nil {
+		h.log.Errorw("failed set sandbox session", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	ticket, err := h.newSandboxTicket(botId, userId, sessionId)
+	if err != nil {
+		h.log.Errorw("failed set sandbox ticket", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(&model.NewSandboxSessionRes{
+		SessionId: sessionId,
+		Ticket:    ticket,
+	})
+}
+
+// New ticket of the connection to the session, e.g. to reconnect
+func (h *ApiHandler) NewSandboxTicket(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	sessionId := ctx.Params("sessionId")
+	session, err := h.r.GetSandboxSession(botId, sessionId)
+	if err != nil {
+		h.log.Errorw("failed get sandbox session", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if session == nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrSandboxSessionNotFound)
+	}
+
+	ticket, err := h.newSandboxTicket(botId, userId, sessionId)
+	if err != nil {
+		h.log.Errorw("failed set sandbox ticket", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(&model.SandboxTicketRes{
+		Ticket: ticket,
+	})
+}
+
+func (h *ApiHandler) DeleteSandboxSession(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	deleted, err := h.r.DelSandboxSession(botId, ctx.Params("sessionId"))
+	if err != nil {
+		h.log.Errorw("failed delete sandbox session", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !deleted {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrSandboxSessionNotFound)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Check of the ticket before the websocket upgrade. Browsers do not send the
+// Authorization header with the websocket request, the connection is authorized by
+// the short-lived single-use ticket issued to the bot owner by the authorized API.
+func (h *ApiHandler) SandboxUpgrade(ctx *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(ctx) {
+		return ctx.SendStatus(fiber.StatusUpgradeRequired)
+	}
+
+	botId, err := strconv.ParseInt(ctx.Params("botId"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	ticket, err := h.r.TakeSandboxTicket(botId, ctx.Query("ticket"))
+	if err != nil {
+		h.log.Errorw("failed take sandbox ticket", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if ticket == nil {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(e.ErrInvalidSandboxTicket)
+	}
+
+	// the bot could be deleted after the ticket was issued
+	existBot, err := h.db.CheckBotExist(ticket.UserId, botId)
+	if err != nil {
+		h.log.Errorw("failed check bot exist", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if !existBot {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrBotNotFound)
+	}
+
+	session, err := h.r.GetSandboxSession(botId, ticket.SessionId)
+	if err != nil {
+		h.log.Errorw("failed get sandbox session", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if session == nil {
+		return ctx.Status(fiber.StatusNotFound).JSON(e.ErrSandboxSessionNotFound)
+	}
+
+	ctx.Locals("botId", botId)
+	ctx.Locals("sessionId", ticket.SessionId)
+
+	return ctx.Next()
+}
+
+// Test chat: every message of the client is an input of the user, it is executed on
+// the current flow of the bot and the result is pushed back. The current state of the
+// session is pushed after the connection.
+func (h *ApiHandler) SandboxChat(conn *websocket.Conn) {
+	botId, _ := conn.Locals("botId").(int64)
+	sessionId, _ := conn.Locals("sessionId").(string)
+
+	event := h.sandboxSend(botId, sessionId, nil)
+	for {
+		if event == nil {
+			_ = conn.WriteMessage(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseInternalServerErr, ""))
+			return
+		}
+
+		if err := conn.WriteJSON(event); err != nil {
+			return
+		}
+
+		if event.Error == e.ErrSandboxSessionNotFound {
+			return
+		}
+
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				h.log.Debugw("sandbox connection closed", "error", err)
+			}
+			return
+		}
+
+		input := new(model.SimulationInput)
+		if err := json.Unmarshal(msg, input); err != nil {
+			event = &sandboxEvent{Error: e.InvalidParam("input")}
+			continue
+		}
+
+		if errValidate := input.Validate(); errValidate != nil {
+			event = &sandboxEvent{Error: errValidate}
+			continue
+		}
+
+		event = h.sandboxSend(botId, sessionId, input)
+	}
+}
+
+// Send the input (nil - only the current state) to the session, nil on internal errors
+func (h *ApiHandler) sandboxSend(botId int64, sessionId string, input *model.SimulationInput) *sandboxEvent {
+	session, err := h.r.GetSandboxSession(botId, sessionId)
+	if err != nil {
+		h.log.Errorw("failed get sandbox session", "error", err)
+		return nil
+	}
+
+	if session == nil {
+		return &sandboxEvent{Error: e.ErrSandboxSessionNotFound}
+	}
+
+	flow, err := h.db.GetFlow(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot flow (sandbox)", "error", err)
+		return nil
+	}
+
+	sim := simulator.New(flow, session.Mocks)
+	if err := sim.Restore(session.State); err != nil {
+		h.log.Errorw("failed restore sandbox session", "error", err)
+		return nil
+	}
+
+	if input != nil {
+		sim.Send(input)
+	}
+
+	session.State = sim.State()
+	if err := h.r.SetSandboxSession(botId, session); err != nil {
+		h.log.Errorw("failed set sandbox session", "error", err)
+		return nil
+	}
+
+	return &sandboxEvent{Result: sim.Result()}
+}
+
+func (h *ApiHandler) newSandboxTicket(botId int64, userId int64, sessionId string) (string, error) {
+	ticket, err := newSessionId()
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.r.SetSandboxTicket(botId, ticket, &model.SandboxTicket{
+		SessionId: sessionId,
+		UserId:    userId,
+	}); err != nil {
+		return "", err
+	}
+
+	return ticket, nil
+}
+
+func newSessionId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/botscubes/bot-service/internal/simulator"
+	"github.com/gofiber/fiber/v2"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+)
+
+// Run the current (not published) flow of the bot against the scripted conversation
+func (h *ApiHandler) SimulateBot(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.SimulateReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	flow, err := h.db.GetFlow(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot flow (simulate)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	sim := simulator.New(flow, reqData.MockResults())
+	if reqData.Variables != nil {
+		if err := sim.SetVariables(reqData.Variables); err != nil {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.InvalidParam("variables"))
+		}
+	}
+
+	for _, input := range *reqData.Inputs {
+		sim.Send(input)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(sim.Result())
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"github.com/botscubes/bot-service/internal/config"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+)
+
+// Users of the bot (Telegram users talking to the bot) with search and pagination
+func (h *ApiHandler) GetUsers(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.GetUsersReq)
+	if err := ctx.QueryParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	users, total, err := h.db.GetUsers(botId, reqData)
+	if err != nil {
+		h.log.Errorw("failed get users", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(&model.GetUsersRes{
+		Users: users,
+		Total: total,
+	})
+}
+
+func (h *ApiHandler) GetUser(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	userId, ok := ctx.Locals("botUserId").(int64)
+	if !ok {
+		h.log.Errorw("BotUserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	user, err := h.db.GetUser(botId, userId)
+	if err != nil {
+		h.log.Errorw("failed get user", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(user)
+}
+
+// Return the user to the start component
+func (h *ApiHandler) ResetUserStep(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	userId, ok := ctx.Locals("botUserId").(int64)
+	if !ok {
+		h.log.Errorw("BotUserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if err := h.setUserStep(botId, userId, config.MainComponentId); err != nil {
+		h.log.Errorw("failed reset user step", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Put the user on the component of the flow executed by the bot
+func (h *ApiHandler) SetUserStep(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	userId, ok := ctx.Locals("botUserId").(int64)
+	if !ok {
+		h.log.Errorw("BotUserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.SetUserStepReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	// the user is moved in the flow executed by the bot, not in the draft
+	version, err := h.db.GetBotVersion(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot version", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if version == nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrNoPublishedVersion)
+	}
+
+	flow, err := h.db.GetVersionFlow(botId, *version)
+	if err != nil {
+		h.log.Errorw("failed get version flow (set user step)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if flow.Component(*reqData.GroupId, *reqData.ComponentId) == nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrComponentNotFound)
+	}
+
+	if err := h.setUserStep(botId, userId, *reqData.ComponentId); err != nil {
+		h.log.Errorw("failed set user step", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// Return all users on the component to the start component, the component may be
+// already deleted
+func (h *ApiHandler) ResetUsersStep(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	reqData := new(model.ResetUsersReq)
+	if err := ctx.BodyParser(reqData); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if errValidate := reqData.Validate(); errValidate != nil {
+		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+	}
+
+	count, err := h.db.SetUsersStep(botId, *reqData.ComponentId, config.MainComponentId)
+	if err != nil {
+		h.log.Errorw("failed reset users step", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(&model.ResetUsersRes{
+		Count: count,
+	})
+}
+
+// The step of the user is set by the Telegram id as the bot worker does
+func (h *ApiHandler) setUserStep(botId int64, userId int64, stepId int64) error {
+	user, err := h.db.GetUser(botId, userId)
+	if err != nil {
+		return err
+	}
+
+	return h.db.SetUserStepByTgId(botId, user.TgId, stepId)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"slices"
+
+	"github.com/botscubes/bot-service/internal/graph"
+	"github.com/gofiber/fiber/v2"
+)
+
+type saveComponentRes struct {
+	Warnings []*graph.VariableWarning `json:"warnings"`
+}
+
+// Variables read and written by the components of the bot
+func (h *ApiHandler) GetVariables(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	flow, err := h.db.GetFlow(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot flow (variables)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(graph.AnalyzeVariables(flow))
+}
+
+// Response to the saved component: 204 without warnings, otherwise 200 with the warnings
+// of the saved component and the warnings about the given variables. Only the group of
+// the component is analyzed (graph.GroupVariableWarnings). The component is already saved,
+// so failures are only logged.
+func (h *ApiHandler) savedComponentRes(
+	ctx *fiber.Ctx, botId int64, groupId int64, componentId int64, variables ...string,
+) error {
+	warnings := []*graph.VariableWarning{}
+
+	components, err := h.db.GetComponents(botId, groupId)
+	if err != nil {
+		h.log.Errorw("failed get bot components (variable warnings)", "error", err)
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+
+	for _, w := range graph.GroupVariableWarnings(groupId, components) {
+		if w.ComponentId == componentId || slices.Contains(variables, w.Variable) {
+			warnings = append(warnings, w)
+		}
+	}
+
+	if len(warnings) == 0 {
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(&saveComponentRes{Warnings: warnings})
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/graph"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+)
+
+type publishBotRes struct {
+	Version int64 `json:"version"`
+}
+
+// Make the version current. A running bot is switched to it through the broker first,
+// the version is saved only if the worker is notified, so the saved version is never
+// ahead of the executed one.
+func (h *ApiHandler) setCurrentVersion(botId int64, userId int64, version int64) error {
+	botStatus, err := h.db.GetBotStatus(botId, userId)
+	if err != nil {
+		return err
+	}
+
+	if botStatus == model.StatusBotRunning {
+		if err := h.mb.SetBotVersion(botId, version); err != nil {
+			return err
+		}
+	}
+
+	return h.db.SetBotVersion(botId, version)
+}
+
+// Snapshot the draft flow into a new published version
+func (h *ApiHandler) PublishBot(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	// the message is optional
+	reqData := new(model.PublishReq)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(reqData); err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		if errValidate := reqData.Validate(); errValidate != nil {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(errValidate)
+		}
+	}
+
+	flow, err := h.db.GetFlow(botId)
+	if err != nil {
+		h.log.Errorw("failed get bot flow (publish)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	// validation of the flow, can be skipped with ?force=true
+	if !ctx.QueryBool("force") {
+		if issues := graph.ValidateFlow(flow); len(issues) > 0 {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(&flowValidationRes{
+				ServiceError: e.ErrFlowValidation,
+				Issues:       issues,
+			})
+		}
+	}
+
+	version, err := h.db.AddVersion(botId, userId, reqData.Message, flow)
+	if err != nil {
+		h.log.Errorw("failed add version", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if err := h.setCurrentVersion(botId, userId, version); err != nil {
+		h.log.Errorw("failed set current version (publish)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(&publishBotRes{
+		Version: version,
+	})
+}
+
+func (h *ApiHandler) GetVersions(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	versions, err := h.db.GetVersions(botId)
+	if err != nil {
+		h.log.Errorw("failed get versions", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(versions)
+}
+
+func (h *ApiHandler) GetVersion(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	number, ok := ctx.Locals("version").(int64)
+	if !ok {
+		h.log.Errorw("Version to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	version, err := h.db.GetVersion(botId, number)
+	if err != nil {
+		h.log.Errorw("failed get version", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(version)
+}
+
+// Make the published version current again
+func (h *ApiHandler) RollbackVersion(ctx *fiber.Ctx) error {
+	userId, ok := ctx.Locals("userId").(int64)
+	if !ok {
+		h.log.Errorw("UserId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	number, ok := ctx.Locals("version").(int64)
+	if !ok {
+		h.log.Errorw("Version to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	if err := h.setCurrentVersion(botId, userId, number); err != nil {
+		h.log.Errorw("failed set current version (rollback)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+const draftVersion = "draft"
+
+// Diff between the version and another version or the draft
+func (h *ApiHandler) DiffVersions(ctx *fiber.Ctx) error {
+	botId, ok := ctx.Locals("botId").(int64)
+	if !ok {
+		h.log.Errorw("BotId to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+	number, ok := ctx.Locals("version").(int64)
+	if !ok {
+		h.log.Errorw("Version to int64 convert", "error", ErrUserIDConvertation)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	var target *model.Flow
+	if ctx.Params("target") == draftVersion {
+		flow, err := h.db.GetFlow(botId)
+		if err != nil {
+			h.log.Errorw("failed get bot flow (diff)", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+		target = flow
+	} else {
+		targetNumber, err := strconv.ParseInt(ctx.Params("target"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		exist, err := h.db.CheckVersionExist(botId, targetNumber)
+		if err != nil {
+			h.log.Errorw("failed check version exist", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		if !exist {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrVersionNotFound)
+		}
+
+		if target, err = h.db.GetVersionFlow(botId, targetNumber); err != nil {
+			h.log.Errorw("failed get version flow (diff)", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	source, err := h.db.GetVersionFlow(botId, number)
+	if err != nil {
+		h.log.Errorw("failed get version flow (diff)", "error", err)
+		return ctx.SendStatus(fiber.StatusInternalServerError)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(graph.Diff(source, target))
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/api/handlers"
+	"github.com/botscubes/bot-service/internal/database/pgsql"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+)
+
+func GetUserMiddleware(db *pgsql.Db, log *zap.SugaredLogger,
+) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		botId, ok := ctx.Locals("botId").(int64)
+		if !ok {
+			log.Errorw("botId to int64 convert", "error", handlers.ErrUserIDConvertation)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		userId, err := strconv.ParseInt(ctx.Params("userId"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+		existUser, err := db.CheckUserExist(botId, userId)
+		if err != nil {
+			log.Errorw("failed check user exist", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+		if !existUser {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrUserNotFound)
+		}
+
+		// "userId" is the id of the authorized user
+		ctx.Locals("botUserId", userId)
+
+		return ctx.Next()
+	}
+}
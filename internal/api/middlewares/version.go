@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/api/handlers"
+	"github.com/botscubes/bot-service/internal/database/pgsql"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+)
+
+func GetVersionMiddleware(db *pgsql.Db, log *zap.SugaredLogger,
+) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		botId, ok := ctx.Locals("botId").(int64)
+		if !ok {
+			log.Errorw("botId to int64 convert", "error", handlers.ErrUserIDConvertation)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+
+		version, err := strconv.ParseInt(ctx.Params("version"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+		existVersion, err := db.CheckVersionExist(botId, version)
+		if err != nil {
+			log.Errorw("failed check version exist", "error", err)
+			return ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+		if !existVersion {
+			return ctx.Status(fiber.StatusUnprocessableEntity).JSON(e.ErrVersionNotFound)
+		}
+
+		ctx.Locals("version", version)
+
+		return ctx.Next()
+	}
+}
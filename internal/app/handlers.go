@@ -5,6 +5,7 @@ import (
 	m "github.com/botscubes/bot-service/internal/api/middlewares"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/websocket/v2"
 )
 
 func (app *App) regiterHandlers(h *handlers.ApiHandler) {
@@ -13,24 +14,42 @@ func (app *App) regiterHandlers(h *handlers.ApiHandler) {
 	// panic recover
 	app.server.Use(recover.New())
 
+	// Websocket of the sandbox session, authorized by the single-use ticket
+	app.server.Get("/api/bots/:botId<int>/sandbox/ws", h.SandboxUpgrade, websocket.New(h.SandboxChat))
+
 	// Auth middleware
 	app.server.Use(m.Auth(&app.sessionStorage, &app.conf.JWTKey, app.log))
 
 	api := app.server.Group("/api")
+	// Registry of the component types
+	api.Get("/component-types", h.GetComponentTypes)
+	// Syntax check of the expressions
+	api.Post("/expressions/check", h.CheckExpression)
+
 	bots := api.Group("/bots")
 	bot := bots.Group("/:botId<int>", m.GetBotMiddleware(app.db, app.log))
 	groups := bot.Group("/groups")
 	group := groups.Group("/:groupId<int>", m.GetGroupMiddleware(app.db, app.log))
+	versions := bot.Group("/versions")
+	version := versions.Group("/:version<int>", m.GetVersionMiddleware(app.db, app.log))
 	components := group.Group("/components")
 	component := components.Group("/:componentId<int>", m.GetComponentMiddleware(app.db, app.log))
+	users := bot.Group("/users")
+	user := users.Group("/:userId<int>", m.GetUserMiddleware(app.db, app.log))
 
 	regBotsHandlers(bots, h)
 	regBotHandlers(bot, h)
+	regVersionsHandlers(versions, h)
+	regVersionHandlers(version, h)
+	regGroupsHandlers(groups, h)
 	regGroupHandlers(group, h)
 	regComponentsHandlers(components, h)
 
 	regComponentHandlers(component, h)
 
+	regUsersHandlers(users, h)
+	regUserHandlers(user, h)
+
 	// custom 404 handler
 	app.server.Use(handlers.NotFoundHandler)
 }
@@ -41,6 +60,8 @@ func regBotsHandlers(bots fiber.Router, h *handlers.ApiHandler) {
 	bots.Post("", h.NewBot)
 	// Get user bots
 	bots.Get("", h.GetBots)
+	// Create new bot from flow document
+	bots.Post("/import", h.ImportBot)
 
 }
 
@@ -63,13 +84,85 @@ func regBotHandlers(bot fiber.Router, h *handlers.ApiHandler) {
 	bot.Patch("/stop", h.StopBot)
 
 	bot.Get("/status", h.GetBotStatus)
+
+	// Publish draft flow
+	bot.Post("/publish", h.PublishBot)
+
+	// Export bot flow
+	bot.Get("/export", h.ExportBot)
+	// Replace bot flow with flow document
+	bot.Post("/import", h.ImportBotFlow)
+	// Clone bot
+	bot.Post("/clone", h.CloneBot)
+
+	// Undo last editor operation
+	bot.Post("/undo", h.Undo)
+	// Redo last undone editor operation
+	bot.Post("/redo", h.Redo)
+
+	// Variables of the bot flow
+	bot.Get("/variables", h.GetVariables)
+
+	// Run the bot flow against a scripted conversation
+	bot.Post("/simulate", h.SimulateBot)
+	// Test chat with the bot flow
+	bot.Post("/sandbox", h.NewSandboxSession)
+	bot.Post("/sandbox/:sessionId/ticket", h.NewSandboxTicket)
+	bot.Delete("/sandbox/:sessionId", h.DeleteSandboxSession)
+
+	// Step-by-step debugging of the bot flow
+	bot.Post("/debug", h.NewDebugSession)
+	bot.Post("/debug/:sessionId/continue", h.ContinueDebugSession)
+	bot.Post("/debug/:sessionId/step", h.StepDebugSession)
+	bot.Delete("/debug/:sessionId", h.DeleteDebugSession)
 }
 
-func regGroupsHandlers(groups fiber.Router, h *handlers.ApiHandler) {
+// Users of the bot (Telegram users talking to the bot)
+func regUsersHandlers(users fiber.Router, h *handlers.ApiHandler) {
+	// Get users with search and pagination
+	users.Get("", h.GetUsers)
+	// Return all users on the component to the start component
+	users.Post("/reset", h.ResetUsersStep)
+}
+
+func regUserHandlers(user fiber.Router, h *handlers.ApiHandler) {
+	// Get user with the current step
+	user.Get("", h.GetUser)
+	// Return the user to the start component
+	user.Post("/reset", h.ResetUserStep)
+	// Put the user on the component
+	user.Patch("/step", h.SetUserStep)
+}
+
+func regVersionsHandlers(versions fiber.Router, h *handlers.ApiHandler) {
+	// Get published versions
+	versions.Get("", h.GetVersions)
+}
 
+func regVersionHandlers(version fiber.Router, h *handlers.ApiHandler) {
+	// Get published version with flow
+	version.Get("", h.GetVersion)
+	// Make version current
+	version.Post("/rollback", h.RollbackVersion)
+	// Diff with another version or the draft
+	version.Get("/diff/:target", h.DiffVersions)
+}
+
+func regGroupsHandlers(groups fiber.Router, h *handlers.ApiHandler) {
+	// Get bot groups
+	groups.Get("", h.GetGroups)
+	// Create new group
+	groups.Post("", h.AddGroup)
 }
 
 func regGroupHandlers(group fiber.Router, h *handlers.ApiHandler) {
+	// Rename group
+	group.Patch("", h.UpdateGroup)
+	// Delete group
+	group.Delete("", h.DeleteGroup)
+	// Static analysis of the group flow
+	group.Get("/analysis", h.GetGroupAnalysis)
+
 	group.Post("/connections", h.AddConnetion)
 	group.Delete("/connections", h.DeleteConnection)
 }
@@ -79,6 +172,14 @@ func regComponentsHandlers(components fiber.Router, h *handlers.ApiHandler) {
 	// Get bot components
 	components.Get("", h.GetBotComponents)
 	components.Post("", h.AddComponent)
+	// Delete a set of components
+	components.Delete("", h.DeleteComponents)
+	// Set positions of a set of components or auto-layout of the group components
+	components.Patch("/positions", h.SetComponentPositions)
+	// Copy components of the bot or another user bot into the group
+	components.Post("/copy", h.CopyComponents)
+	// Move components to another group
+	components.Post("/move", h.MoveComponents)
 
 }
 
@@ -87,4 +188,6 @@ func regComponentHandlers(component fiber.Router, h *handlers.ApiHandler) {
 	component.Patch("/position", h.SetComponentPosition)
 	component.Patch("/data", h.UpdateComponentData)
 	component.Patch("/path", h.UpdateComponentPath)
+	// Pause the debugger before the component
+	component.Patch("/breakpoint", h.SetComponentBreakpoint)
 }
@@ -1,7 +1,8 @@
 package broker
 
 type Broker interface {
-	StartBot(botId int64, token string) error
+	StartBot(botId int64, token string, version int64) error
 	StopBot(botId int64) error
+	SetBotVersion(botId int64, version int64) error
 	CloseConnection()
 }
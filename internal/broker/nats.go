@@ -32,14 +32,16 @@ func (b *NatsBroker) CloseConnection() {
 }
 
 type startBotPayload struct {
-	BotId int64  `json:"botId"`
-	Token string `json:"token"`
+	BotId   int64  `json:"botId"`
+	Token   string `json:"token"`
+	Version int64  `json:"version"`
 }
 
-func (b *NatsBroker) StartBot(botId int64, token string) error {
+func (b *NatsBroker) StartBot(botId int64, token string, version int64) error {
 	payload, err := json.Marshal(startBotPayload{
-		BotId: botId,
-		Token: token,
+		BotId:   botId,
+		Token:   token,
+		Version: version,
 	})
 	if err != nil {
 		return err
@@ -80,3 +82,30 @@ func (b *NatsBroker) StopBot(botId int64) error {
 
 	return nil
 }
+
+type setBotVersionPayload struct {
+	BotId   int64 `json:"botId"`
+	Version int64 `json:"version"`
+}
+
+// Switch the running bot to the version
+func (b *NatsBroker) SetBotVersion(botId int64, version int64) error {
+	payload, err := json.Marshal(setBotVersionPayload{
+		BotId:   botId,
+		Version: version,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := b.nc.Request("worker.version", payload, config.NatsReqTimeout)
+	if err != nil {
+		return err
+	}
+
+	if string(res.Data) != natsCodeOk {
+		return fmt.Errorf("nats get res error: %v", string(res.Data))
+	}
+
+	return nil
+}
@@ -15,6 +15,15 @@ const (
 	RedisExpire     = 1 * time.Hour
 	ShutdownTimeout = 1 * time.Minute
 	NatsReqTimeout  = 5 * time.Second
+
+	JournalMaxLen = 100 // Max number of undo (redo) operations per bot and user
+	JournalExpire = 24 * time.Hour
+
+	DebugSessionsMaxPerBot = 5 // Max number of debug sessions per bot, the least recently used is dropped
+	DebugSessionExpire     = 30 * time.Minute
+	DebugCommandLockExpire = 10 * time.Second // Lock of the session while a command runs
+
+	SandboxTicketExpire = 30 * time.Second // Single-use ticket of the sandbox websocket connection
 )
 
 type ServiceConfig struct {
@@ -36,8 +36,13 @@ func (db *Db) CreateBot(m *model.Bot, mc *model.Component) (botId int64, compone
 	if _, err = tx.Exec(ctx, query); err != nil {
 		return 0, 0, err
 	}
-	var groupId int
+
 	bot := prefixSchema + strconv.FormatInt(botId, 10)
+	if err = migrateBotSchemaTx(ctx, tx, bot); err != nil {
+		return 0, 0, err
+	}
+
+	var groupId int
 	query = `INSERT INTO ` + bot + `.component_group
 			(name) VALUES ('main') RETURNING id;`
 	if err = tx.QueryRow(
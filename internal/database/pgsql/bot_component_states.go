@@ -0,0 +1,316 @@
+package pgsql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// The components are not in the expected states
+	ErrComponentStatesChanged = errors.New("component states changed")
+	// The groups of the components were deleted
+	ErrComponentGroupsDeleted = errors.New("component groups deleted")
+)
+
+// The states cannot be set, Ids - the components that caused the error
+type ComponentStatesError struct {
+	Err error
+	Ids []int64
+}
+
+func (e *ComponentStatesError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ComponentStatesError) Unwrap() error {
+	return e.Err
+}
+
+// Editor operation in the transaction, returns the ids of the created components
+type EditorOp func(ctx context.Context, tx pgx.Tx) ([]int64, error)
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Current states of the components, a component that does not exist has a nil state
+func (db *Db) GetComponentStates(botId int64, ids []int64) ([]*model.ComponentState, error) {
+	return componentStates(context.Background(), db.Pool, botId, ids, "")
+}
+
+// Run the editor operation in one transaction and read the states of the changed
+// components before and after it in the same transaction. ids - the components changed
+// by the operation, if linked is set the components connected to them are changed too.
+// The components are locked before the operation, so a concurrent operation cannot
+// change them between the reads. Only the states of the components that were actually
+// changed are returned.
+func (db *Db) RunEditorOp(
+	botId int64, ids []int64, linked bool, op EditorOp,
+) (before []*model.ComponentState, after []*model.ComponentState, err error) {
+	ctx := context.Background()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	before, err = componentStates(ctx, tx, botId, ids, "FOR UPDATE OF c")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if linked {
+		ids = append([]int64{}, ids...)
+		for _, s := range before {
+			if s.Component != nil {
+				ids = append(ids, s.Component.LinkedIds()...)
+			}
+		}
+
+		before, err = componentStates(ctx, tx, botId, ids, "FOR UPDATE OF c")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	created, err := op(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids = make([]int64, 0, len(before)+len(created))
+	for _, s := range before {
+		ids = append(ids, s.Id)
+	}
+	for _, id := range created {
+		before = append(before, &model.ComponentState{Id: id})
+		ids = append(ids, id)
+	}
+
+	after, err = componentStates(ctx, tx, botId, ids, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	before, after = model.ChangedStates(before, after)
+	return before, after, nil
+}
+
+// States of the components read by the pool or in a transaction, lock - locking
+// clause of the query
+func componentStates(
+	ctx context.Context, q querier, botId int64, ids []int64, lock string,
+) ([]*model.ComponentState, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `
+		SELECT 
+			c.group_id,
+			c.component_id, 
+			c.type, 
+			c.path, 
+			c.position,
+			c.data,
+			c.connection_points,
+			c.outputs,
+			c.breakpoint,
+			EXISTS(
+				SELECT 1 FROM ` + schema + `.component_group g WHERE g.entry_component_id = c.component_id
+			)
+		FROM ` + schema + `.component c WHERE c.component_id = ANY($1) ` + lock + `;`
+
+	rows, err := q.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make(map[int64]*model.ComponentState)
+
+	for rows.Next() {
+		var groupId int64
+		var entry bool
+		var c model.Component
+		if err = rows.Scan(
+			&groupId, &c.Id, &c.Type, &c.Path, &c.Position, &c.Data, &c.ConnectionPoints, &c.Outputs,
+			&c.Breakpoint, &entry,
+		); err != nil {
+			return nil, err
+		}
+
+		found[c.Id] = &model.ComponentState{
+			Id:        c.Id,
+			GroupId:   groupId,
+			Component: &c,
+			Entry:     entry,
+		}
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	data := []*model.ComponentState{}
+	seen := make(map[int64]bool)
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if s, ok := found[id]; ok {
+			data = append(data, s)
+		} else {
+			data = append(data, &model.ComponentState{Id: id})
+		}
+	}
+
+	return data, nil
+}
+
+// Bring the components to the states in one transaction: components with a nil state
+// are deleted, the others are inserted or updated. The current states are checked
+// against the expected ones in the same transaction, ComponentStatesError with
+// ErrComponentStatesChanged if the components were changed after the journaled
+// operation and with ErrComponentGroupsDeleted if the groups of the restored
+// components were deleted.
+func (db *Db) SetComponentStates(botId int64, expected []*model.ComponentState, states []*model.ComponentState) (err error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	ctx := context.Background()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	ids := make([]int64, 0, len(expected))
+	for _, s := range expected {
+		ids = append(ids, s.Id)
+	}
+
+	current, err := componentStates(ctx, tx, botId, ids, "FOR UPDATE OF c")
+	if err != nil {
+		return err
+	}
+
+	currentById := make(map[int64]*model.ComponentState, len(current))
+	for _, s := range current {
+		currentById[s.Id] = s
+	}
+
+	changed := []int64{}
+	for _, s := range expected {
+		if c, ok := currentById[s.Id]; !ok || !s.Matches(c) {
+			changed = append(changed, s.Id)
+		}
+	}
+
+	if len(changed) > 0 {
+		err = &ComponentStatesError{Err: ErrComponentStatesChanged, Ids: changed}
+		return err
+	}
+
+	// the groups are locked, so they cannot be deleted before the commit
+	groupIds := []int64{}
+	for _, s := range states {
+		if s.Component != nil {
+			groupIds = append(groupIds, s.GroupId)
+		}
+	}
+
+	rows, err := tx.Query(
+		ctx, `SELECT id FROM `+schema+`.component_group WHERE id = ANY($1) FOR SHARE;`, groupIds,
+	)
+	if err != nil {
+		return err
+	}
+
+	existGroups := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		existGroups[id] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	deletedGroups := []int64{}
+	for _, s := range states {
+		if s.Component != nil && !existGroups[s.GroupId] {
+			deletedGroups = append(deletedGroups, s.Id)
+		}
+	}
+
+	if len(deletedGroups) > 0 {
+		err = &ComponentStatesError{Err: ErrComponentGroupsDeleted, Ids: deletedGroups}
+		return err
+	}
+
+	deleteQuery := `DELETE FROM ` + schema + `.component WHERE component_id = $1;`
+	// the breakpoint is restored with a deleted component and kept otherwise
+	upsertQuery := `
+		INSERT INTO ` + schema + `.component
+			(component_id, group_id, type, path, position, data, connection_points, outputs, breakpoint)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (component_id) DO UPDATE SET
+			group_id = EXCLUDED.group_id,
+			type = EXCLUDED.type,
+			path = EXCLUDED.path,
+			position = EXCLUDED.position,
+			data = EXCLUDED.data,
+			connection_points = EXCLUDED.connection_points,
+			outputs = EXCLUDED.outputs;`
+	// the entry is set null when the component is deleted,
+	// the restored component becomes the entry if the group has no other one
+	entryQuery := `
+		UPDATE ` + schema + `.component_group SET entry_component_id = $1
+		WHERE id = $2 AND entry_component_id IS NULL;`
+
+	for _, s := range states {
+		if s.Component == nil {
+			if _, err = tx.Exec(ctx, deleteQuery, s.Id); err != nil {
+				return err
+			}
+			continue
+		}
+
+		c := s.Component
+		if _, err = tx.Exec(
+			ctx, upsertQuery, s.Id, s.GroupId, c.Type, c.Path, c.Position, c.Data, c.ConnectionPoints, c.Outputs, c.Breakpoint,
+		); err != nil {
+			return err
+		}
+
+		if s.Entry {
+			if _, err = tx.Exec(ctx, entryQuery, s.Id, s.GroupId); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/botscubes/bot-service/internal/model"
+	"github.com/jackc/pgx/v5"
 )
 
 //				id bigserial NOT NULL,
@@ -16,15 +17,15 @@ import (
 //	           group_id BIGINT,
 //	           PRIMARY KEY (id),
 //	           FOREIGN KEY(group_id)
-func (db *Db) AddComponent(botId int64, groupId int64, m *model.Component) (int64, error) {
+func (db *Db) AddComponentTx(ctx context.Context, tx pgx.Tx, botId int64, groupId int64, m *model.Component) (int64, error) {
 
 	schema := prefixSchema + strconv.FormatInt(botId, 10)
 	var id int64
 	query := `INSERT INTO ` + schema + `.component
 			(type, path, position, group_id) VALUES ($1, $2, $3, $4) RETURNING component_id;`
 
-	if err := db.Pool.QueryRow(
-		context.Background(), query, m.Type, m.Path, m.Position, groupId,
+	if err := tx.QueryRow(
+		ctx, query, m.Type, m.Path, m.Position, groupId,
 	).Scan(&id); err != nil {
 		return 0, err
 	}
@@ -32,29 +33,34 @@ func (db *Db) AddComponent(botId int64, groupId int64, m *model.Component) (int6
 	return id, nil
 }
 
-func (db *Db) DeleteComponent(botId int64, groupId int64, componentId int64) error {
+// Delete the components with all inbound and outbound connections
+func (db *Db) DeleteComponentsTx(ctx context.Context, tx pgx.Tx, botId int64, groupId int64, ids []int64) error {
 
 	schema := prefixSchema + strconv.FormatInt(botId, 10)
-	ctx := context.Background()
 
-	tx, err := db.Pool.Begin(ctx)
-	if err != nil {
-		return err
-	}
+	deleted := make(map[int64]bool)
+	for _, componentId := range ids {
+		if deleted[componentId] {
+			continue
+		}
+		deleted[componentId] = true
 
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback(ctx)
-		} else {
-			_ = tx.Commit(ctx)
+		if err := deleteComponentTx(ctx, tx, schema, groupId, componentId); err != nil {
+			return err
 		}
-	}()
+	}
 
+	return nil
+}
+
+func deleteComponentTx(ctx context.Context, tx pgx.Tx, schema string, groupId int64, componentId int64) error {
 	var m map[string]model.ConnectionPoint
 	query := `
 		SELECT connection_points FROM ` + schema + `.component
 		WHERE group_id = $1 AND component_id = $2;`
-	err = tx.QueryRow(context.Background(), query, groupId, componentId).Scan(&m)
+	if err := tx.QueryRow(ctx, query, groupId, componentId).Scan(&m); err != nil {
+		return err
+	}
 	query = `
 		UPDATE ` + schema + `.component 
 		SET outputs = outputs - $1
@@ -62,10 +68,9 @@ func (db *Db) DeleteComponent(botId int64, groupId int64, componentId int64) err
 
 	for _, val := range m {
 
-		_, err = tx.Exec(
+		if _, err := tx.Exec(
 			ctx, query, val.SourcePointName, groupId, val.SourceComponentId,
-		)
-		if err != nil {
+		); err != nil {
 			return err
 		}
 	}
@@ -74,27 +79,121 @@ func (db *Db) DeleteComponent(botId int64, groupId int64, componentId int64) err
 	query = `
 		SELECT outputs FROM ` + schema + `.component
 		WHERE group_id = $1 AND component_id = $2;`
-	err = tx.QueryRow(context.Background(), query, groupId, componentId).Scan(&outputs)
+	if err := tx.QueryRow(ctx, query, groupId, componentId).Scan(&outputs); err != nil {
+		return err
+	}
 	query = `UPDATE ` + schema + `.component 
 		SET connection_points = connection_points - $1
 		WHERE group_id = $2 AND component_id = $3;`
 
 	for name, val := range outputs {
 		var idx = strconv.FormatInt(componentId, 10) + " " + name
-		_, err = tx.Exec(
+		if _, err := tx.Exec(
 			ctx, query, idx, groupId, val,
-		)
-		if err != nil {
+		); err != nil {
 			return err
 		}
 	}
 	query = `DELETE FROM ` + schema + `.component
 			WHERE group_id = $1 AND component_id = $2;`
 
-	_, err = tx.Exec(context.Background(), query, groupId, componentId)
+	_, err := tx.Exec(ctx, query, groupId, componentId)
 	return err
+}
 
+// Insert copies of the components into the group.
+// Connections between the copied components are kept, the others are dropped.
+// Returns the ids of the copies by the ids of the source components.
+func (db *Db) CopyComponentsTx(
+	ctx context.Context, tx pgx.Tx, botId int64, groupId int64, comps []*model.Component,
+) (map[int64]int64, error) {
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+
+	ids := make(map[int64]int64, len(comps))
+	query := `INSERT INTO ` + schema + `.component
+			(type, path, position, group_id) VALUES ($1, $2, $3, $4) RETURNING component_id;`
+	for _, c := range comps {
+		var id int64
+		if err := tx.QueryRow(
+			ctx, query, c.Type, c.Path, c.Position, groupId,
+		).Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[c.Id] = id
+	}
+
+	query = `
+		UPDATE ` + schema + `.component
+		SET data = $1, outputs = $2, connection_points = $3
+		WHERE component_id = $4;`
+	for _, c := range comps {
+		c.RemapIds(ids)
+
+		data := c.Data
+		if data == nil {
+			data = map[string]any{}
+		}
+
+		if _, err := tx.Exec(
+			ctx, query, data, c.Outputs, c.ConnectionPoints, c.Id,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, nil
+}
+
+// Move the components to another group.
+// If breakConnections is set, connections between the moved and the remaining
+// components of the group are deleted.
+func (db *Db) MoveComponentsTx(
+	ctx context.Context, tx pgx.Tx, botId int64, groupId int64, targetGroupId int64, ids []int64, breakConnections bool,
+) error {
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+
+	if breakConnections {
+		// keep the outputs and connection points whose ends are both moved or both remain
+		query := `
+			UPDATE ` + schema + `.component
+			SET outputs = (
+				SELECT COALESCE(JSONB_OBJECT_AGG(key, value), '{}'::JSONB)
+				FROM JSONB_EACH(outputs)
+				WHERE (value::BIGINT = ANY($2)) = (component_id = ANY($2))
+			),
+			connection_points = (
+				SELECT COALESCE(JSONB_OBJECT_AGG(key, value), '{}'::JSONB)
+				FROM JSONB_EACH(connection_points)
+				WHERE ((value->>'sourceComponentId')::BIGINT = ANY($2)) = (component_id = ANY($2))
+			)
+			WHERE group_id = $1;`
+
+		if _, err := tx.Exec(ctx, query, groupId, ids); err != nil {
+			return err
+		}
+	}
+
+	// the entry of the group must be a component of the group
+	query := `
+		UPDATE ` + schema + `.component_group
+		SET entry_component_id = NULL
+		WHERE id = $1 AND entry_component_id = ANY($2);`
+
+	if _, err := tx.Exec(ctx, query, groupId, ids); err != nil {
+		return err
+	}
+
+	query = `
+		UPDATE ` + schema + `.component
+		SET group_id = $2
+		WHERE group_id = $1 AND component_id = ANY($3);`
+
+	_, err := tx.Exec(ctx, query, groupId, targetGroupId, ids)
+	return err
 }
+
 func (db *Db) GetComponents(botId int64, groupId int64) ([]*model.Component, error) {
 
 	schema := prefixSchema + strconv.FormatInt(botId, 10)
@@ -107,7 +206,8 @@ func (db *Db) GetComponents(botId int64, groupId int64) ([]*model.Component, err
 			position,
 			data,
 			connection_points,
-			outputs
+			outputs,
+			breakpoint
 		FROM ` + schema + `.component WHERE group_id = $1;`
 
 	rows, err := db.Pool.Query(context.Background(), query, groupId)
@@ -119,7 +219,9 @@ func (db *Db) GetComponents(botId int64, groupId int64) ([]*model.Component, err
 
 	for rows.Next() {
 		var c model.Component
-		if err = rows.Scan(&c.Id, &c.Type, &c.Path, &c.Position, &c.Data, &c.ConnectionPoints, &c.Outputs); err != nil {
+		if err = rows.Scan(
+			&c.Id, &c.Type, &c.Path, &c.Position, &c.Data, &c.ConnectionPoints, &c.Outputs, &c.Breakpoint,
+		); err != nil {
 			return nil, err
 		}
 
@@ -150,7 +252,26 @@ func (db *Db) GetComponentType(botId int64, groupId int64, componentId int64) (s
 	return cType, nil
 }
 
-func (db *Db) SetComponentPosition(botId int64, groupId int64, componentId int64, position *model.Point) error {
+func (db *Db) GetComponentData(botId int64, groupId int64, componentId int64) (map[string]any, error) {
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `
+		SELECT 
+			data 
+		FROM ` + schema + `.component 
+		WHERE group_id = $1 AND component_id = $2;`
+	var data map[string]any
+	err := db.Pool.QueryRow(context.Background(), query, groupId, componentId).Scan(&data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (db *Db) SetComponentPositionTx(
+	ctx context.Context, tx pgx.Tx, botId int64, groupId int64, componentId int64, position *model.Point,
+) error {
 
 	schema := prefixSchema + strconv.FormatInt(botId, 10)
 
@@ -159,9 +280,77 @@ func (db *Db) SetComponentPosition(botId int64, groupId int64, componentId int64
 			SET position = $3
 			WHERE group_id = $1 AND component_id = $2;`
 
-	_, err := db.Pool.Exec(context.Background(), query, groupId, componentId, position)
+	_, err := tx.Exec(ctx, query, groupId, componentId, position)
+	return err
+
+}
+
+func (db *Db) SetComponentBreakpoint(botId int64, groupId int64, componentId int64, breakpoint bool) error {
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+
+	query := `
+			UPDATE ` + schema + `.component
+			SET breakpoint = $3
+			WHERE group_id = $1 AND component_id = $2;`
+
+	_, err := db.Pool.Exec(context.Background(), query, groupId, componentId, breakpoint)
 	return err
+}
+
+// Ids of the components of all groups with breakpoints
+func (db *Db) GetBreakpoints(botId int64) ([]int64, error) {
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT component_id FROM ` + schema + `.component WHERE breakpoint ORDER BY component_id;`
+
+	rows, err := db.Pool.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return ids, nil
+}
+
+// Set positions of several components in one statement
+func (db *Db) SetComponentPositionsTx(
+	ctx context.Context, tx pgx.Tx, botId int64, groupId int64, positions []*model.ComponentPosition,
+) error {
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+
+	ids := make([]int64, 0, len(positions))
+	xs := make([]float64, 0, len(positions))
+	ys := make([]float64, 0, len(positions))
+	for _, p := range positions {
+		ids = append(ids, *p.Id)
+		xs = append(xs, p.X)
+		ys = append(ys, p.Y)
+	}
 
+	query := `
+			UPDATE ` + schema + `.component AS c
+			SET position = POINT(p.x, p.y)
+			FROM UNNEST($2::BIGINT[], $3::FLOAT8[], $4::FLOAT8[]) AS p(id, x, y)
+			WHERE c.group_id = $1 AND c.component_id = p.id;`
+
+	_, err := tx.Exec(ctx, query, groupId, ids, xs, ys)
+	return err
 }
 
 func (db *Db) CheckComponentExist(botId int64, groupId int64, compId int64) (bool, error) {
@@ -179,7 +368,27 @@ func (db *Db) CheckComponentExist(botId int64, groupId int64, compId int64) (boo
 	return c, nil
 }
 
-func (db *Db) UpdateComponentData(botId int64, groupId int64, componentId int64, data map[string]any) error {
+// Check that every component exists in the group
+func (db *Db) CheckComponentsExist(botId int64, groupId int64, ids []int64) (bool, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	var c bool
+	query := `SELECT NOT EXISTS(
+			SELECT UNNEST($2::BIGINT[])
+			EXCEPT
+			SELECT component_id FROM ` + schema + `.component WHERE group_id = $1);`
+
+	if err := db.Pool.QueryRow(
+		context.Background(), query, groupId, ids,
+	).Scan(&c); err != nil {
+		return false, err
+	}
+
+	return c, nil
+}
+
+func (db *Db) UpdateComponentDataTx(
+	ctx context.Context, tx pgx.Tx, botId int64, groupId int64, componentId int64, data map[string]any,
+) error {
 
 	schema := prefixSchema + strconv.FormatInt(botId, 10)
 	query := `
@@ -187,8 +396,8 @@ func (db *Db) UpdateComponentData(botId int64, groupId int64, componentId int64,
 		SET data = data || $1
 		WHERE group_id = $2 AND component_id = $3;`
 
-	_, err := db.Pool.Exec(
-		context.Background(), query, data, groupId, componentId,
+	_, err := tx.Exec(
+		ctx, query, data, groupId, componentId,
 	)
 	if err != nil {
 		return err
@@ -196,7 +405,9 @@ func (db *Db) UpdateComponentData(botId int64, groupId int64, componentId int64,
 	return nil
 }
 
-func (db *Db) UpdateComponentPath(botId int64, groupId int64, componentId int64, path string) error {
+func (db *Db) UpdateComponentPathTx(
+	ctx context.Context, tx pgx.Tx, botId int64, groupId int64, componentId int64, path string,
+) error {
 
 	schema := prefixSchema + strconv.FormatInt(botId, 10)
 	query := `
@@ -204,8 +415,8 @@ func (db *Db) UpdateComponentPath(botId int64, groupId int64, componentId int64,
 		SET path = $1
 		WHERE group_id = $2 AND component_id = $3;`
 
-	_, err := db.Pool.Exec(
-		context.Background(), query, path, groupId, componentId,
+	_, err := tx.Exec(
+		ctx, query, path, groupId, componentId,
 	)
 	if err != nil {
 		return err
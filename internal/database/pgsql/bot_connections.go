@@ -5,24 +5,10 @@ import (
 	"strconv"
 
 	"github.com/botscubes/bot-service/internal/model"
+	"github.com/jackc/pgx/v5"
 )
 
-func (db *Db) AddConnection(botId int64, groupId int64, m *model.Connection) error {
-
-	ctx := context.Background()
-
-	tx, err := db.Pool.Begin(ctx)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback(ctx)
-		} else {
-			_ = tx.Commit(ctx)
-		}
-	}()
+func (db *Db) AddConnectionTx(ctx context.Context, tx pgx.Tx, botId int64, groupId int64, m *model.Connection) error {
 
 	schema := prefixSchema + strconv.FormatInt(botId, 10)
 	idx := "{\"" + strconv.FormatInt(*m.SourceComponentId, 10) + " " + *m.SourcePointName + "\"}"
@@ -31,7 +17,7 @@ func (db *Db) AddConnection(botId int64, groupId int64, m *model.Connection) err
 		SET connection_points = JSONB_SET(connection_points, $1, $2) 
 		WHERE group_id = $3 AND component_id = $4;`
 
-	_, err = tx.Exec(
+	_, err := tx.Exec(
 		ctx, query, idx, m.ConnectionPoint, groupId, m.TargetComponentId,
 	)
 	if err != nil {
@@ -71,21 +57,9 @@ func (db *Db) GetTargetComponentId(botId int64, groupId int64, m *model.SourceCo
 	return targetComponentId, nil
 }
 
-func (db *Db) DeleteConnection(botId int64, groupId int64, m *model.SourceConnectionPoint, targetComponentId int64) error {
-	ctx := context.Background()
-
-	tx, err := db.Pool.Begin(ctx)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if err != nil {
-			_ = tx.Rollback(ctx)
-		} else {
-			_ = tx.Commit(ctx)
-		}
-	}()
+func (db *Db) DeleteConnectionTx(
+	ctx context.Context, tx pgx.Tx, botId int64, groupId int64, m *model.SourceConnectionPoint, targetComponentId int64,
+) error {
 	schema := prefixSchema + strconv.FormatInt(botId, 10)
 
 	idx := strconv.FormatInt(*m.SourceComponentId, 10) + " " + *m.SourcePointName
@@ -94,7 +68,7 @@ func (db *Db) DeleteConnection(botId int64, groupId int64, m *model.SourceConnec
 		SET connection_points = connection_points - $1
 		WHERE group_id = $2 AND component_id = $3;`
 
-	_, err = tx.Exec(
+	_, err := tx.Exec(
 		ctx, query, idx, groupId, targetComponentId,
 	)
 	if err != nil {
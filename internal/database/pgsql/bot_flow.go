@@ -0,0 +1,284 @@
+package pgsql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/config"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// Get the whole bot flow: title, groups and components of every group.
+// Everything is read in one transaction to get a consistent snapshot.
+func (db *Db) GetFlow(botId int64) (*model.Flow, error) {
+	ctx := context.Background()
+
+	tx, err := db.Pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+
+	flow := &model.Flow{
+		Format:  model.FlowFormat,
+		Version: model.FlowVersion,
+		Groups:  []*model.FlowGroup{},
+	}
+
+	query := `SELECT title FROM public.bot WHERE id = $1;`
+	if err = tx.QueryRow(ctx, query, botId).Scan(&flow.Title); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT id, name, entry_component_id FROM ` + schema + `.component_group ORDER BY id;`
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[int64]*model.FlowGroup)
+	for rows.Next() {
+		g := &model.FlowGroup{
+			Components: []*model.Component{},
+		}
+		if err = rows.Scan(&g.Id, &g.Name, &g.EntryComponentId); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		groups[g.Id] = g
+		flow.Groups = append(flow.Groups, g)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	query = `
+		SELECT 
+			group_id,
+			component_id, 
+			type, 
+			path, 
+			position,
+			data,
+			connection_points,
+			outputs
+		FROM ` + schema + `.component ORDER BY component_id;`
+
+	rows, err = tx.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for rows.Next() {
+		var groupId int64
+		var c model.Component
+		if err = rows.Scan(
+			&groupId, &c.Id, &c.Type, &c.Path, &c.Position, &c.Data, &c.ConnectionPoints, &c.Outputs,
+		); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		if g, ok := groups[groupId]; ok {
+			g.Components = append(g.Components, &c)
+		}
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return flow, nil
+}
+
+// Create a new bot with the flow in one transaction. The flow must be valid.
+func (db *Db) CreateBotFromFlow(m *model.Bot, f *model.Flow) (botId int64, err error) {
+	ctx := context.Background()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	query := `INSERT INTO public.bot (user_id, token, title, status) VALUES ($1, $2, $3, $4) RETURNING id;`
+	if err = tx.QueryRow(
+		ctx, query, m.UserId, m.Token, m.Title, m.Status,
+	).Scan(&botId); err != nil {
+		return 0, err
+	}
+
+	query = `CALL create_bot_schema(` + strconv.FormatInt(botId, 10) + `);`
+	if _, err = tx.Exec(ctx, query); err != nil {
+		return 0, err
+	}
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	if err = migrateBotSchemaTx(ctx, tx, schema); err != nil {
+		return 0, err
+	}
+
+	startGroup, start := f.Start()
+
+	var mainGroupId int64
+	query = `INSERT INTO ` + schema + `.component_group (name) VALUES ($1) RETURNING id;`
+	if err = tx.QueryRow(ctx, query, startGroup.Name).Scan(&mainGroupId); err != nil {
+		return 0, err
+	}
+
+	var startId int64
+	query = `INSERT INTO ` + schema + `.component
+			(type, path, position, group_id) VALUES ($1, $2, $3, $4) RETURNING component_id;`
+	if err = tx.QueryRow(
+		ctx, query, start.Type, start.Path, start.Position, mainGroupId,
+	).Scan(&startId); err != nil {
+		return 0, err
+	}
+
+	if err = insertFlowTx(ctx, tx, schema, f, mainGroupId, startId); err != nil {
+		return 0, err
+	}
+
+	return botId, nil
+}
+
+// Replace all groups and components of the bot with the flow in one transaction.
+// The start component and the main group keep their ids. The flow must be valid.
+func (db *Db) ReplaceFlow(botId int64, f *model.Flow) (err error) {
+	ctx := context.Background()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+
+	var mainGroupId int64
+	query := `SELECT group_id FROM ` + schema + `.component WHERE component_id = $1;`
+	if err = tx.QueryRow(ctx, query, config.MainComponentId).Scan(&mainGroupId); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM ` + schema + `.component WHERE component_id <> $1;`
+	if _, err = tx.Exec(ctx, query, config.MainComponentId); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM ` + schema + `.component_group WHERE id <> $1;`
+	if _, err = tx.Exec(ctx, query, mainGroupId); err != nil {
+		return err
+	}
+
+	return insertFlowTx(ctx, tx, schema, f, mainGroupId, config.MainComponentId)
+}
+
+// Insert groups and components of the flow into the bot schema.
+// The main group and the start component must already exist,
+// ids of the flow are remapped to the ids assigned by the database.
+func insertFlowTx(ctx context.Context, tx pgx.Tx, schema string, f *model.Flow, mainGroupId int64, startId int64) error {
+	startGroup, start := f.Start()
+
+	groupIds := map[int64]int64{startGroup.Id: mainGroupId}
+	ids := map[int64]int64{start.Id: startId}
+
+	query := `UPDATE ` + schema + `.component_group SET name = $1 WHERE id = $2;`
+	if _, err := tx.Exec(ctx, query, startGroup.Name, mainGroupId); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO ` + schema + `.component_group (name) VALUES ($1) RETURNING id;`
+	for _, g := range f.Groups {
+		if g == startGroup {
+			continue
+		}
+
+		var id int64
+		if err := tx.QueryRow(ctx, query, g.Name).Scan(&id); err != nil {
+			return err
+		}
+		groupIds[g.Id] = id
+	}
+
+	query = `INSERT INTO ` + schema + `.component
+			(type, path, position, group_id) VALUES ($1, $2, $3, $4) RETURNING component_id;`
+	for _, g := range f.Groups {
+		for _, c := range g.Components {
+			if c == start {
+				continue
+			}
+
+			var id int64
+			if err := tx.QueryRow(
+				ctx, query, c.Type, c.Path, c.Position, groupIds[g.Id],
+			).Scan(&id); err != nil {
+				return err
+			}
+			ids[c.Id] = id
+		}
+	}
+
+	query = `
+		UPDATE ` + schema + `.component
+		SET path = $1, position = $2, data = $3, outputs = $4, connection_points = $5
+		WHERE component_id = $6;`
+	for _, g := range f.Groups {
+		for _, c := range g.Components {
+			c.RemapIds(ids)
+			c.RemapSubflowGroupId(groupIds)
+
+			data := c.Data
+			if data == nil {
+				data = map[string]any{}
+			}
+
+			if _, err := tx.Exec(
+				ctx, query, c.Path, c.Position, data, c.Outputs, c.ConnectionPoints, c.Id,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	query = `UPDATE ` + schema + `.component_group SET entry_component_id = $1 WHERE id = $2;`
+	for _, g := range f.Groups {
+		var entryId *int64
+		if g.EntryComponentId != nil {
+			if id, ok := ids[*g.EntryComponentId]; ok {
+				entryId = &id
+			}
+		}
+
+		if _, err := tx.Exec(ctx, query, entryId, groupIds[g.Id]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package pgsql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/config"
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+func (db *Db) GetGroups(botId int64) ([]*model.Group, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT id, name, entry_component_id FROM ` + schema + `.component_group ORDER BY id;`
+
+	rows, err := db.Pool.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []*model.Group{}
+
+	for rows.Next() {
+		var g model.Group
+		if err = rows.Scan(&g.Id, &g.Name, &g.EntryComponentId); err != nil {
+			return nil, err
+		}
+
+		data = append(data, &g)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return data, nil
+}
+
+func (db *Db) AddGroup(botId int64, name string) (int64, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `INSERT INTO ` + schema + `.component_group (name) VALUES ($1) RETURNING id;`
+
+	var id int64
+	if err := db.Pool.QueryRow(context.Background(), query, name).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (db *Db) SetGroupName(botId int64, groupId int64, name string) error {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `UPDATE ` + schema + `.component_group SET name = $1 WHERE id = $2;`
+
+	_, err := db.Pool.Exec(context.Background(), query, name, groupId)
+	return err
+}
+
+func (db *Db) SetGroupEntry(botId int64, groupId int64, componentId int64) error {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `UPDATE ` + schema + `.component_group SET entry_component_id = $1 WHERE id = $2;`
+
+	_, err := db.Pool.Exec(context.Background(), query, componentId, groupId)
+	return err
+}
+
+// Entry component of the group, nil if it is not set
+func (db *Db) GetGroupEntry(botId int64, groupId int64) (*int64, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT entry_component_id FROM ` + schema + `.component_group WHERE id = $1;`
+
+	var id *int64
+	if err := db.Pool.QueryRow(context.Background(), query, groupId).Scan(&id); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// The main group is the group that holds the start component.
+func (db *Db) GetMainGroupId(botId int64) (int64, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT group_id FROM ` + schema + `.component WHERE component_id = $1;`
+
+	var id int64
+	if err := db.Pool.QueryRow(
+		context.Background(), query, config.MainComponentId,
+	).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (db *Db) GetCountComponentsInGroup(botId int64, groupId int64) (int64, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT count(id) FROM ` + schema + `.component WHERE group_id = $1;`
+
+	var data int64
+	if err := db.Pool.QueryRow(context.Background(), query, groupId).Scan(&data); err != nil {
+		return 0, err
+	}
+
+	return data, nil
+}
+
+// Delete group with all its components.
+// Connections never cross group boundaries, so other groups are not affected.
+func (db *Db) DeleteGroup(botId int64, groupId int64) error {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	ctx := context.Background()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	query := `DELETE FROM ` + schema + `.component WHERE group_id = $1;`
+	if _, err = tx.Exec(ctx, query, groupId); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM ` + schema + `.component_group WHERE id = $1;`
+	_, err = tx.Exec(ctx, query, groupId)
+	return err
+}
+
+// Sub-flow components of other groups that call the group
+func (db *Db) GetSubflowCallers(botId int64, groupId int64) ([]int64, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `
+		SELECT component_id FROM ` + schema + `.component
+		WHERE type = $1 AND group_id <> $2 AND data->'groupId' = TO_JSONB($2::BIGINT)
+		ORDER BY component_id;`
+
+	rows, err := db.Pool.Query(context.Background(), query, model.TypeSubflow, groupId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,142 @@
+package pgsql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+// Save the flow as the next published version
+func (db *Db) AddVersion(botId int64, userId int64, message *string, f *model.Flow) (number int64, err error) {
+	ctx := context.Background()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		} else {
+			_ = tx.Commit(ctx)
+		}
+	}()
+
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+
+	// version numbers are sequential, concurrent publications wait for each other
+	query := `LOCK TABLE ` + schema + `.version IN SHARE ROW EXCLUSIVE MODE;`
+	if _, err = tx.Exec(ctx, query); err != nil {
+		return 0, err
+	}
+
+	query = `
+		INSERT INTO ` + schema + `.version (number, flow, user_id, message)
+		SELECT COALESCE(MAX(number), 0) + 1, $1, $2, $3 FROM ` + schema + `.version
+		RETURNING number;`
+	if err = tx.QueryRow(ctx, query, f, userId, message).Scan(&number); err != nil {
+		return 0, err
+	}
+
+	return number, nil
+}
+
+// Number of the last published version, 0 if the bot has no versions
+func (db *Db) GetLastVersionNumber(botId int64) (int64, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT COALESCE(MAX(number), 0) FROM ` + schema + `.version;`
+
+	var number int64
+	if err := db.Pool.QueryRow(context.Background(), query).Scan(&number); err != nil {
+		return 0, err
+	}
+
+	return number, nil
+}
+
+func (db *Db) GetVersionFlow(botId int64, number int64) (*model.Flow, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT flow FROM ` + schema + `.version WHERE number = $1;`
+
+	var f model.Flow
+	if err := db.Pool.QueryRow(context.Background(), query, number).Scan(&f); err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// Published versions without flows, the last version first
+func (db *Db) GetVersions(botId int64) ([]*model.Version, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT number, user_id, created_at, message FROM ` + schema + `.version ORDER BY number DESC;`
+
+	rows, err := db.Pool.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := []*model.Version{}
+
+	for rows.Next() {
+		var v model.Version
+		if err = rows.Scan(&v.Number, &v.UserId, &v.CreatedAt, &v.Message); err != nil {
+			return nil, err
+		}
+
+		data = append(data, &v)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return data, nil
+}
+
+func (db *Db) GetVersion(botId int64, number int64) (*model.Version, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT number, user_id, created_at, message, flow FROM ` + schema + `.version WHERE number = $1;`
+
+	var v model.Version
+	if err := db.Pool.QueryRow(context.Background(), query, number).Scan(
+		&v.Number, &v.UserId, &v.CreatedAt, &v.Message, &v.Flow,
+	); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+func (db *Db) CheckVersionExist(botId int64, number int64) (bool, error) {
+	schema := prefixSchema + strconv.FormatInt(botId, 10)
+	query := `SELECT EXISTS(SELECT 1 FROM ` + schema + `.version WHERE number = $1);`
+
+	var c bool
+	if err := db.Pool.QueryRow(context.Background(), query, number).Scan(&c); err != nil {
+		return false, err
+	}
+
+	return c, nil
+}
+
+// Version executed by the bot, nil if it is not set
+func (db *Db) GetBotVersion(botId int64) (*int64, error) {
+	query := `SELECT version FROM public.bot WHERE id = $1;`
+
+	var number *int64
+	if err := db.Pool.QueryRow(context.Background(), query, botId).Scan(&number); err != nil {
+		return nil, err
+	}
+
+	return number, nil
+}
+
+// Set the version executed by the bot
+func (db *Db) SetBotVersion(botId int64, number int64) error {
+	query := `UPDATE public.bot SET version = $1 WHERE id = $2;`
+	_, err := db.Pool.Exec(context.Background(), query, number, botId)
+	return err
+}
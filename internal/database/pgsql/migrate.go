@@ -0,0 +1,86 @@
+package pgsql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Changes of the public schema. Statements must be idempotent.
+var publicMigrations = []string{
+	// version of the flow executed by the running bot
+	`ALTER TABLE public.bot ADD COLUMN IF NOT EXISTS version BIGINT;`,
+}
+
+// Changes of the bot schema made after create_bot_schema. Statements must be idempotent,
+// they are applied to every bot schema at startup and to the schema of every new bot.
+func botSchemaMigrations(schema string) []string {
+	return []string{
+		// published versions of the flow
+		`CREATE TABLE IF NOT EXISTS ` + schema + `.version (
+			number BIGINT NOT NULL,
+			flow JSONB NOT NULL,
+			user_id BIGINT NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (number)
+		);`,
+		`ALTER TABLE ` + schema + `.version ADD COLUMN IF NOT EXISTS message TEXT;`,
+		// component the group flow starts from when the group is called as a sub-flow
+		`ALTER TABLE ` + schema + `.component_group ADD COLUMN IF NOT EXISTS entry_component_id BIGINT
+			REFERENCES ` + schema + `.component (component_id) ON DELETE SET NULL;`,
+		// the debugger pauses before the component
+		`ALTER TABLE ` + schema + `.component ADD COLUMN IF NOT EXISTS breakpoint BOOLEAN NOT NULL DEFAULT false;`,
+	}
+}
+
+// Apply migrations to the public schema and to all bot schemas
+func (db *Db) Migrate() error {
+	ctx := context.Background()
+
+	for _, query := range publicMigrations {
+		if _, err := db.Pool.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	query := `SELECT nspname FROM pg_catalog.pg_namespace WHERE nspname ~ '^` + prefixSchema + `[0-9]+$';`
+	rows, err := db.Pool.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	schemas := []string{}
+	for rows.Next() {
+		var schema string
+		if err = rows.Scan(&schema); err != nil {
+			rows.Close()
+			return err
+		}
+
+		schemas = append(schemas, schema)
+	}
+
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	for _, schema := range schemas {
+		for _, query := range botSchemaMigrations(schema) {
+			if _, err = db.Pool.Exec(ctx, query); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func migrateBotSchemaTx(ctx context.Context, tx pgx.Tx, schema string) error {
+	for _, query := range botSchemaMigrations(schema) {
+		if _, err := tx.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
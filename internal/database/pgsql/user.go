@@ -3,6 +3,7 @@ package pgsql
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/botscubes/bot-service/internal/model"
 )
@@ -75,3 +76,100 @@ func (db *Db) SetUserStepByTgId(botId int64, userId int64, stepID int64) error {
 	_, err := db.Pool.Exec(context.Background(), query, stepID, userId)
 	return err
 }
+
+// Move all users on the component to another component, returns the number of the moved users
+func (db *Db) SetUsersStep(botId int64, fromStepId int64, toStepId int64) (int64, error) {
+	prefix := prefixSchema + strconv.FormatInt(botId, 10)
+
+	query := `UPDATE ` + prefix + `.user SET step_id = $1 WHERE step_id = $2;`
+	tag, err := db.Pool.Exec(context.Background(), query, toStepId, fromStepId)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Users of the bot ordered by id and the number of all users matching the query.
+// The search matches a part of the username or the Telegram id.
+func (db *Db) GetUsers(botId int64, r *model.GetUsersReq) ([]*model.User, int64, error) {
+	prefix := prefixSchema + strconv.FormatInt(botId, 10)
+	ctx := context.Background()
+
+	search := strings.TrimPrefix(strings.TrimSpace(r.Search), "@")
+	pattern := "%" + likeEscaper.Replace(search) + "%"
+	var tgId *int64
+	if id, err := strconv.ParseInt(search, 10, 64); err == nil {
+		tgId = &id
+	}
+
+	where := `
+		WHERE ($1 = '' OR username ILIKE $2 OR tg_id = $3)
+		AND ($4::INT IS NULL OR status = $4)`
+
+	var total int64
+	query := `SELECT COUNT(*) FROM ` + prefix + `.user` + where + `;`
+	if err := db.Pool.QueryRow(ctx, query, search, pattern, tgId, r.Status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query = `
+		SELECT id, tg_id, first_name, last_name, username, step_id, status
+		FROM ` + prefix + `.user` + where + `
+		ORDER BY id LIMIT $5 OFFSET $6;`
+
+	rows, err := db.Pool.Query(ctx, query, search, pattern, tgId, r.Status, r.Limit, r.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users := []*model.User{}
+	for rows.Next() {
+		var u model.User
+		if err = rows.Scan(
+			&u.Id, &u.TgId, &u.FirstName, &u.LastName, &u.Username, &u.StepId, &u.Status,
+		); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+
+		users = append(users, &u)
+	}
+
+	if rows.Err() != nil {
+		return nil, 0, rows.Err()
+	}
+
+	return users, total, nil
+}
+
+// Escape of the special characters of the LIKE pattern (the default escape character is backslash)
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (db *Db) GetUser(botId int64, userId int64) (*model.User, error) {
+	prefix := prefixSchema + strconv.FormatInt(botId, 10)
+
+	query := `SELECT id, tg_id, first_name, last_name, username, step_id, status
+			FROM ` + prefix + `.user WHERE id = $1;`
+
+	var u model.User
+	if err := db.Pool.QueryRow(
+		context.Background(), query, userId,
+	).Scan(&u.Id, &u.TgId, &u.FirstName, &u.LastName, &u.Username, &u.StepId, &u.Status); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (db *Db) CheckUserExist(botId int64, userId int64) (bool, error) {
+	var c bool
+	prefix := prefixSchema + strconv.FormatInt(botId, 10)
+
+	query := `SELECT EXISTS(SELECT 1 FROM ` + prefix + `.user WHERE id = $1);`
+	if err := db.Pool.QueryRow(context.Background(), query, userId).Scan(&c); err != nil {
+		return false, err
+	}
+
+	return c, nil
+}
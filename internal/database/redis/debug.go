@@ -0,0 +1,128 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/botscubes/bot-service/internal/config"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/redis/go-redis/v9"
+)
+
+func debugKey(botId int64, sessionId string) string {
+	return "bot" + strconv.FormatInt(botId, 10) + ":debug:" + sessionId
+}
+
+// Sessions of the bot by the time of the last use
+func debugSessionsKey(botId int64) string {
+	return "bot" + strconv.FormatInt(botId, 10) + ":debug_sessions"
+}
+
+func debugLockKey(botId int64, sessionId string) string {
+	return "bot" + strconv.FormatInt(botId, 10) + ":debug_lock:" + sessionId
+}
+
+// Save a new session, the least recently used sessions are deleted if the bot has
+// too many sessions
+func (rdb *Rdb) AddDebugSession(botId int64, session *model.DebugSession) error {
+	ctx := context.Background()
+	key := debugSessionsKey(botId)
+
+	// the sessions of the set expire by themselves
+	expired := strconv.FormatInt(time.Now().Add(-config.DebugSessionExpire).UnixMilli(), 10)
+	if err := rdb.ZRemRangeByScore(ctx, key, "-inf", expired).Err(); err != nil {
+		return err
+	}
+
+	count, err := rdb.ZCard(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	if count >= config.DebugSessionsMaxPerBot {
+		oldest, err := rdb.ZPopMin(ctx, key, count-config.DebugSessionsMaxPerBot+1).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, z := range oldest {
+			if sessionId, ok := z.Member.(string); ok {
+				if err := rdb.Del(ctx, debugKey(botId, sessionId)).Err(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := rdb.Set(ctx, debugKey(botId, session.Id), session, config.DebugSessionExpire).Err(); err != nil {
+		return err
+	}
+
+	return rdb.touchDebugSession(ctx, botId, session.Id)
+}
+
+// Save the session after a command, false if the session does not exist anymore
+func (rdb *Rdb) SetDebugSession(botId int64, session *model.DebugSession) (bool, error) {
+	ctx := context.Background()
+
+	ok, err := rdb.SetXX(ctx, debugKey(botId, session.Id), session, config.DebugSessionExpire).Result()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return true, rdb.touchDebugSession(ctx, botId, session.Id)
+}
+
+func (rdb *Rdb) touchDebugSession(ctx context.Context, botId int64, sessionId string) error {
+	key := debugSessionsKey(botId)
+	if err := rdb.ZAdd(ctx, key, redis.Z{
+		Score:  float64(time.Now().UnixMilli()),
+		Member: sessionId,
+	}).Err(); err != nil {
+		return err
+	}
+
+	return rdb.Expire(ctx, key, config.DebugSessionExpire).Err()
+}
+
+// Get the session, nil if the session does not exist or expired
+func (rdb *Rdb) GetDebugSession(botId int64, sessionId string) (*model.DebugSession, error) {
+	var session model.DebugSession
+	if err := rdb.Get(context.Background(), debugKey(botId, sessionId)).Scan(&session); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Delete the session, false if the session does not exist
+func (rdb *Rdb) DelDebugSession(botId int64, sessionId string) (bool, error) {
+	ctx := context.Background()
+
+	n, err := rdb.Del(ctx, debugKey(botId, sessionId)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	if err := rdb.ZRem(ctx, debugSessionsKey(botId), sessionId).Err(); err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// Lock the session while a command runs, so the commands of the session sent to different
+// instances of the service are not run at once. False if the session is already locked.
+func (rdb *Rdb) LockDebugSession(botId int64, sessionId string) (bool, error) {
+	return rdb.SetNX(context.Background(), debugLockKey(botId, sessionId), 1, config.DebugCommandLockExpire).Result()
+}
+
+func (rdb *Rdb) UnlockDebugSession(botId int64, sessionId string) error {
+	return rdb.Del(context.Background(), debugLockKey(botId, sessionId)).Err()
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/config"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/redis/go-redis/v9"
+)
+
+func journalKey(botId int64, userId int64, stack string) string {
+	return "bot" + strconv.FormatInt(botId, 10) + ":journal:" + strconv.FormatInt(userId, 10) + ":" + stack
+}
+
+func (rdb *Rdb) pushJournalEntry(key string, entry *model.JournalEntry) error {
+	ctx := context.Background()
+
+	if err := rdb.LPush(ctx, key, entry).Err(); err != nil {
+		return err
+	}
+
+	if err := rdb.LTrim(ctx, key, 0, config.JournalMaxLen-1).Err(); err != nil {
+		return err
+	}
+
+	return rdb.Expire(ctx, key, config.JournalExpire).Err()
+}
+
+// Pop the last entry, nil if the stack is empty
+func (rdb *Rdb) popJournalEntry(key string) (*model.JournalEntry, error) {
+	var entry model.JournalEntry
+	if err := rdb.LPop(context.Background(), key).Scan(&entry); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Add the entry of a new operation, operations undone before can no longer be redone
+func (rdb *Rdb) AddJournalEntry(botId int64, userId int64, entry *model.JournalEntry) error {
+	if err := rdb.Del(context.Background(), journalKey(botId, userId, "redo")).Err(); err != nil {
+		return err
+	}
+
+	return rdb.pushJournalEntry(journalKey(botId, userId, "undo"), entry)
+}
+
+func (rdb *Rdb) PushUndoEntry(botId int64, userId int64, entry *model.JournalEntry) error {
+	return rdb.pushJournalEntry(journalKey(botId, userId, "undo"), entry)
+}
+
+func (rdb *Rdb) PopUndoEntry(botId int64, userId int64) (*model.JournalEntry, error) {
+	return rdb.popJournalEntry(journalKey(botId, userId, "undo"))
+}
+
+func (rdb *Rdb) PushRedoEntry(botId int64, userId int64, entry *model.JournalEntry) error {
+	return rdb.pushJournalEntry(journalKey(botId, userId, "redo"), entry)
+}
+
+func (rdb *Rdb) PopRedoEntry(botId int64, userId int64) (*model.JournalEntry, error) {
+	return rdb.popJournalEntry(journalKey(botId, userId, "redo"))
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/config"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/redis/go-redis/v9"
+)
+
+func sandboxKey(botId int64, sessionId string) string {
+	return "bot" + strconv.FormatInt(botId, 10) + ":sandbox:" + sessionId
+}
+
+// Save the session, the session expires if it is not used
+func (rdb *Rdb) SetSandboxSession(botId int64, session *model.SandboxSession) error {
+	return rdb.Set(context.Background(), sandboxKey(botId, session.Id), session, config.RedisExpire).Err()
+}
+
+// Get the session, nil if the session does not exist or expired
+func (rdb *Rdb) GetSandboxSession(botId int64, sessionId string) (*model.SandboxSession, error) {
+	var session model.SandboxSession
+	if err := rdb.Get(context.Background(), sandboxKey(botId, sessionId)).Scan(&session); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Delete the session, false if the session does not exist
+func (rdb *Rdb) DelSandboxSession(botId int64, sessionId string) (bool, error) {
+	n, err := rdb.Del(context.Background(), sandboxKey(botId, sessionId)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func sandboxTicketKey(botId int64, ticket string) string {
+	return "bot" + strconv.FormatInt(botId, 10) + ":sandbox_ticket:" + ticket
+}
+
+// Save the ticket of the connection, the ticket expires shortly
+func (rdb *Rdb) SetSandboxTicket(botId int64, ticket string, t *model.SandboxTicket) error {
+	return rdb.Set(context.Background(), sandboxTicketKey(botId, ticket), t, config.SandboxTicketExpire).Err()
+}
+
+// Get and delete the ticket, nil if the ticket does not exist, expired or was used
+func (rdb *Rdb) TakeSandboxTicket(botId int64, ticket string) (*model.SandboxTicket, error) {
+	var t model.SandboxTicket
+	if err := rdb.GetDel(context.Background(), sandboxTicketKey(botId, ticket)).Scan(&t); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &t, nil
+}
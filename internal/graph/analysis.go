@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+type MissingData struct {
+	ComponentId int64  `json:"componentId"`
+	Field       string `json:"field"`
+}
+
+type DanglingOutput struct {
+	ComponentId int64  `json:"componentId"`
+	Output      string `json:"output"`
+	TargetId    int64  `json:"targetId"`
+}
+
+type Report struct {
+	Unreachable     []int64           `json:"unreachable"`
+	DeadEnds        []int64           `json:"deadEnds"`
+	MissingData     []*MissingData    `json:"missingData"`
+	DanglingOutputs []*DanglingOutput `json:"danglingOutputs"`
+}
+
+// Static analysis of the group flow
+func (g *Graph) Analyze() *Report {
+	r := &Report{
+		Unreachable:     []int64{},
+		DeadEnds:        []int64{},
+		MissingData:     []*MissingData{},
+		DanglingOutputs: []*DanglingOutput{},
+	}
+
+	reachable := g.Reachable()
+
+	for _, id := range g.ids {
+		c := g.Components[id]
+
+		if !reachable[id] {
+			r.Unreachable = append(r.Unreachable, id)
+		}
+
+		for _, field := range model.MissingComponentData(c.Type, c.Data) {
+			r.MissingData = append(r.MissingData, &MissingData{
+				ComponentId: id,
+				Field:       field,
+			})
+		}
+
+		deadEnd := true
+		for _, name := range OutputNames(c) {
+			targetId := c.Outputs[name]
+			if _, ok := g.Components[targetId]; !ok {
+				r.DanglingOutputs = append(r.DanglingOutputs, &DanglingOutput{
+					ComponentId: id,
+					Output:      name,
+					TargetId:    targetId,
+				})
+				continue
+			}
+
+			if IsNextOutput(c.Type, name) {
+				deadEnd = false
+			}
+		}
+
+		if deadEnd {
+			r.DeadEnds = append(r.DeadEnds, id)
+		}
+	}
+
+	return r
+}
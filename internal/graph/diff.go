@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+type ComponentRef struct {
+	Id      int64  `json:"id"`
+	GroupId int64  `json:"groupId"`
+	Type    string `json:"type"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+type ComponentChange struct {
+	ComponentRef
+	Changes []*FieldChange `json:"changes"`
+}
+
+type ConnectionRef struct {
+	SourceComponentId int64  `json:"sourceComponentId"`
+	SourcePointName   string `json:"sourcePointName"`
+	TargetComponentId int64  `json:"targetComponentId"`
+}
+
+type FlowDiff struct {
+	ComponentsAdded    []*ComponentRef    `json:"componentsAdded"`
+	ComponentsRemoved  []*ComponentRef    `json:"componentsRemoved"`
+	ComponentsModified []*ComponentChange `json:"componentsModified"`
+	ConnectionsAdded   []*ConnectionRef   `json:"connectionsAdded"`
+	ConnectionsRemoved []*ConnectionRef   `json:"connectionsRemoved"`
+}
+
+type flowComponent struct {
+	groupId   int64
+	component *model.Component
+}
+
+// Components of the flow by id
+func flowComponents(f *model.Flow) map[int64]*flowComponent {
+	comps := make(map[int64]*flowComponent)
+	for _, g := range f.Groups {
+		for _, c := range g.Components {
+			comps[c.Id] = &flowComponent{
+				groupId:   g.Id,
+				component: c,
+			}
+		}
+	}
+
+	return comps
+}
+
+func sortedIds(comps map[int64]*flowComponent) []int64 {
+	ids := make([]int64, 0, len(comps))
+	for id := range comps {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+func (fc *flowComponent) ref() *ComponentRef {
+	return &ComponentRef{
+		Id:      fc.component.Id,
+		GroupId: fc.groupId,
+		Type:    fc.component.Type,
+	}
+}
+
+// Structured diff between two flows. Components are matched by id.
+func Diff(a *model.Flow, b *model.Flow) *FlowDiff {
+	d := &FlowDiff{
+		ComponentsAdded:    []*ComponentRef{},
+		ComponentsRemoved:  []*ComponentRef{},
+		ComponentsModified: []*ComponentChange{},
+		ConnectionsAdded:   []*ConnectionRef{},
+		ConnectionsRemoved: []*ConnectionRef{},
+	}
+
+	compsA := flowComponents(a)
+	compsB := flowComponents(b)
+
+	for _, id := range sortedIds(compsA) {
+		old := compsA[id]
+		cur, ok := compsB[id]
+		if !ok {
+			d.ComponentsRemoved = append(d.ComponentsRemoved, old.ref())
+			d.ConnectionsRemoved = append(d.ConnectionsRemoved, connections(old.component, nil)...)
+			continue
+		}
+
+		if changes := componentChanges(old, cur); len(changes) > 0 {
+			d.ComponentsModified = append(d.ComponentsModified, &ComponentChange{
+				ComponentRef: *cur.ref(),
+				Changes:      changes,
+			})
+		}
+
+		d.ConnectionsRemoved = append(d.ConnectionsRemoved, connections(old.component, cur.component)...)
+		d.ConnectionsAdded = append(d.ConnectionsAdded, connections(cur.component, old.component)...)
+	}
+
+	for _, id := range sortedIds(compsB) {
+		if _, ok := compsA[id]; ok {
+			continue
+		}
+
+		cur := compsB[id]
+		d.ComponentsAdded = append(d.ComponentsAdded, cur.ref())
+		d.ConnectionsAdded = append(d.ConnectionsAdded, connections(cur.component, nil)...)
+	}
+
+	return d
+}
+
+// Connections of the component that the other version of the component does not have
+func connections(c *model.Component, other *model.Component) []*ConnectionRef {
+	refs := []*ConnectionRef{}
+	for _, name := range OutputNames(c) {
+		targetId := c.Outputs[name]
+		if other != nil {
+			if otherTargetId, ok := other.Outputs[name]; ok && otherTargetId == targetId {
+				continue
+			}
+		}
+
+		refs = append(refs, &ConnectionRef{
+			SourceComponentId: c.Id,
+			SourcePointName:   name,
+			TargetComponentId: targetId,
+		})
+	}
+
+	return refs
+}
+
+func componentChanges(a *flowComponent, b *flowComponent) []*FieldChange {
+	changes := []*FieldChange{}
+
+	if a.groupId != b.groupId {
+		changes = append(changes, &FieldChange{Field: "groupId", Old: a.groupId, New: b.groupId})
+	}
+
+	if a.component.Type != b.component.Type {
+		changes = append(changes, &FieldChange{Field: "type", Old: a.component.Type, New: b.component.Type})
+	}
+
+	if a.component.Path != b.component.Path {
+		changes = append(changes, &FieldChange{Field: "path", Old: a.component.Path, New: b.component.Path})
+	}
+
+	if !positionsEqual(a.component.Position, b.component.Position) {
+		changes = append(changes, &FieldChange{Field: "position", Old: a.component.Position, New: b.component.Position})
+	}
+
+	keys := make(map[string]bool)
+	for key := range a.component.Data {
+		keys[key] = true
+	}
+	for key := range b.component.Data {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		oldValue, okOld := a.component.Data[key]
+		newValue, okNew := b.component.Data[key]
+		if okOld == okNew && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		changes = append(changes, &FieldChange{Field: "data." + key, Old: oldValue, New: newValue})
+	}
+
+	return changes
+}
+
+func positionsEqual(a *model.Point, b *model.Point) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.X == b.X && a.Y == b.Y
+}
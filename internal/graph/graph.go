@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/botscubes/bot-service/internal/config"
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+// Graph of the components of one group. Edges are the component outputs.
+type Graph struct {
+	Components map[int64]*model.Component
+	EntryId    int64 // Entry component of the group called as a sub-flow, 0 if it is not set
+	ids        []int64
+}
+
+func New(comps []*model.Component) *Graph {
+	g := &Graph{
+		Components: make(map[int64]*model.Component, len(comps)),
+		ids:        make([]int64, 0, len(comps)),
+	}
+
+	for _, c := range comps {
+		g.Components[c.Id] = c
+		g.ids = append(g.ids, c.Id)
+	}
+
+	sort.Slice(g.ids, func(i, j int) bool { return g.ids[i] < g.ids[j] })
+
+	return g
+}
+
+// Component ids in ascending order
+func (g *Graph) Ids() []int64 {
+	return g.ids
+}
+
+// Output names of the component in a stable order
+func OutputNames(c *model.Component) []string {
+	names := make([]string, 0, len(c.Outputs))
+	for name := range c.Outputs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Check that the output leads further along the flow.
+// Components with numeric outputs (buttons) lead further with them, other components with nextComponentId.
+func IsNextOutput(componentType string, name string) bool {
+	if info := model.GetComponentTypeInfo(componentType); info != nil && info.NumericOutputs {
+		_, err := strconv.Atoi(name)
+		return err == nil
+	}
+
+	return name == "nextComponentId"
+}
+
+// Components the flow of the group starts from: the start component in the main group,
+// the entry component of the group, otherwise the components without incoming connections.
+func (g *Graph) Entries() []int64 {
+	if _, ok := g.Components[config.MainComponentId]; ok {
+		return []int64{config.MainComponentId}
+	}
+
+	if _, ok := g.Components[g.EntryId]; ok {
+		return []int64{g.EntryId}
+	}
+
+	incoming := make(map[int64]bool)
+	for _, c := range g.Components {
+		for _, targetId := range c.Outputs {
+			if targetId != c.Id {
+				incoming[targetId] = true
+			}
+		}
+	}
+
+	entries := []int64{}
+	for _, id := range g.ids {
+		if !incoming[id] {
+			entries = append(entries, id)
+		}
+	}
+
+	return entries
+}
+
+// Components reachable from the entries by the outputs
+func (g *Graph) Reachable() map[int64]bool {
+	visited := make(map[int64]bool)
+	stack := g.Entries()
+
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		c, ok := g.Components[id]
+		if !ok || visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		for _, targetId := range c.Outputs {
+			stack = append(stack, targetId)
+		}
+	}
+
+	return visited
+}
@@ -0,0 +1,68 @@
+package graph
+
+import "github.com/botscubes/bot-service/internal/model"
+
+const (
+	LayoutOriginX = 100
+	LayoutOriginY = 100
+	LayoutStepX   = 300 // Distance between the components of one layer
+	LayoutStepY   = 200 // Distance between the layers
+)
+
+// Layered positions of the components: the entries are in the first layer,
+// every other component is one layer below the nearest component leading to it.
+// Components not reachable from the entries start new layers after the others.
+func (g *Graph) Layout() []*model.ComponentPosition {
+	layers := [][]int64{}
+	placed := make(map[int64]bool, len(g.ids))
+
+	place := func(start []int64) {
+		layer := []int64{}
+		for _, id := range start {
+			if _, ok := g.Components[id]; ok && !placed[id] {
+				placed[id] = true
+				layer = append(layer, id)
+			}
+		}
+
+		for len(layer) > 0 {
+			layers = append(layers, layer)
+
+			next := []int64{}
+			for _, id := range layer {
+				c := g.Components[id]
+				for _, name := range OutputNames(c) {
+					targetId := c.Outputs[name]
+					if _, ok := g.Components[targetId]; ok && !placed[targetId] {
+						placed[targetId] = true
+						next = append(next, targetId)
+					}
+				}
+			}
+			layer = next
+		}
+	}
+
+	place(g.Entries())
+	for _, id := range g.ids {
+		if !placed[id] {
+			place([]int64{id})
+		}
+	}
+
+	positions := make([]*model.ComponentPosition, 0, len(g.ids))
+	for y, layer := range layers {
+		for x, id := range layer {
+			id := id
+			positions = append(positions, &model.ComponentPosition{
+				Id: &id,
+				Point: model.Point{
+					X: float64(LayoutOriginX + x*LayoutStepX),
+					Y: float64(LayoutOriginY + y*LayoutStepY),
+				},
+			})
+		}
+	}
+
+	return positions
+}
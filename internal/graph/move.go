@@ -0,0 +1,28 @@
+package graph
+
+// Connections between the selected components and the other components of the group
+func (g *Graph) CrossingConnections(ids []int64) []*ConnectionRef {
+	selected := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		selected[id] = true
+	}
+
+	refs := []*ConnectionRef{}
+	for _, id := range g.ids {
+		c := g.Components[id]
+		for _, name := range OutputNames(c) {
+			targetId := c.Outputs[name]
+			if _, ok := g.Components[targetId]; !ok || selected[id] == selected[targetId] {
+				continue
+			}
+
+			refs = append(refs, &ConnectionRef{
+				SourceComponentId: id,
+				SourcePointName:   name,
+				TargetComponentId: targetId,
+			})
+		}
+	}
+
+	return refs
+}
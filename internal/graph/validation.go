@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/botscubes/bot-components/components"
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+// Problem of the component that prevents the bot from running correctly
+type Issue struct {
+	GroupId     int64  `json:"groupId"`
+	ComponentId int64  `json:"componentId"`
+	Field       string `json:"field"`
+	Message     string `json:"message"`
+	// Position of the syntax error in the expression
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// Whole-graph checks of the group flow before the bot is started
+func (g *Graph) Validate(groupId int64) []*Issue {
+	issues := []*Issue{}
+	add := func(componentId int64, field string, message string) {
+		issues = append(issues, &Issue{
+			GroupId:     groupId,
+			ComponentId: componentId,
+			Field:       field,
+			Message:     message,
+		})
+	}
+
+	for _, id := range g.ids {
+		c := g.Components[id]
+
+		if c.Type == components.TypeStart {
+			if _, ok := c.Outputs["nextComponentId"]; !ok {
+				add(id, "outputs.nextComponentId", "The start component is not connected")
+			}
+		}
+
+		for _, err := range model.ValidateComponentData(c.Type, c.Data, true) {
+			add(id, "data"+strings.ReplaceAll(err.Pointer, "/", "."), err.Message)
+			issues[len(issues)-1].Line = err.Line
+			issues[len(issues)-1].Column = err.Column
+		}
+
+		info := model.GetComponentTypeInfo(c.Type)
+		for _, name := range OutputNames(c) {
+			if info == nil {
+				add(id, "outputs."+name, "Unknown component type")
+				continue
+			}
+
+			if err := info.ValidateOutput(name); err != nil {
+				add(id, "outputs."+name, err.Message)
+			} else if info.NumericOutputs && !slices.Contains(info.Outputs, name) && !model.HasButton(c.Data, name) {
+				add(id, "outputs."+name, "Button not found")
+			}
+
+			if _, exists := g.Components[c.Outputs[name]]; !exists {
+				add(id, "outputs."+name, "Target component not found")
+			}
+		}
+
+		// every button must lead somewhere
+		if info != nil && info.NumericOutputs {
+			for _, index := range model.ButtonIndexes(c.Data) {
+				if _, ok := c.Outputs[index]; !ok {
+					add(id, "outputs."+index, "The button is not connected")
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+// Whole-graph checks of every group of the flow
+func ValidateFlow(f *model.Flow) []*Issue {
+	issues := []*Issue{}
+	groups := make(map[int64]*model.FlowGroup, len(f.Groups))
+	for _, g := range f.Groups {
+		groups[g.Id] = g
+	}
+	calls := subflowCalls(f)
+
+	for _, g := range f.Groups {
+		issues = append(issues, New(g.Components).Validate(g.Id)...)
+
+		// a called group must exist, have an entry component and not call the group back
+		for _, c := range g.Components {
+			subflowGroupId := c.SubflowGroupId()
+			if subflowGroupId == 0 {
+				continue
+			}
+
+			target, ok := groups[subflowGroupId]
+			if !ok {
+				issues = append(issues, &Issue{
+					GroupId:     g.Id,
+					ComponentId: c.Id,
+					Field:       "data.groupId",
+					Message:     "Group not found",
+				})
+			} else if target.EntryComponentId == nil {
+				issues = append(issues, &Issue{
+					GroupId:     g.Id,
+					ComponentId: c.Id,
+					Field:       "data.groupId",
+					Message:     "The group has no entry component",
+				})
+			} else if subflowGroupId == g.Id || calls[subflowGroupId][g.Id] {
+				// every call is pushed to the stack of the user, a recursive call never returns
+				issues = append(issues, &Issue{
+					GroupId:     g.Id,
+					ComponentId: c.Id,
+					Field:       "data.groupId",
+					Message:     "The sub-flow call is recursive",
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// Groups called by every group of the flow, directly or through nested sub-flows
+func subflowCalls(f *model.Flow) map[int64]map[int64]bool {
+	direct := make(map[int64][]int64, len(f.Groups))
+	for _, g := range f.Groups {
+		for _, c := range g.Components {
+			if subflowGroupId := c.SubflowGroupId(); subflowGroupId != 0 {
+				direct[g.Id] = append(direct[g.Id], subflowGroupId)
+			}
+		}
+	}
+
+	calls := make(map[int64]map[int64]bool, len(f.Groups))
+	for _, g := range f.Groups {
+		called := make(map[int64]bool)
+		queue := append([]int64{}, direct[g.Id]...)
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+
+			if called[id] {
+				continue
+			}
+			called[id] = true
+			queue = append(queue, direct[id]...)
+		}
+
+		calls[g.Id] = called
+	}
+
+	return calls
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+func subflowGroup(id int64, calls ...int64) *model.FlowGroup {
+	entry := id * 10
+	g := &model.FlowGroup{Id: id, EntryComponentId: &entry}
+	for i, groupId := range calls {
+		c := &model.Component{
+			Id:   entry + int64(i),
+			Data: map[string]any{"groupId": float64(groupId)},
+		}
+		c.Type = model.TypeSubflow
+		g.Components = append(g.Components, c)
+	}
+
+	return g
+}
+
+func recursiveCalls(f *model.Flow) []int64 {
+	ids := []int64{}
+	for _, issue := range ValidateFlow(f) {
+		if issue.Message == "The sub-flow call is recursive" {
+			ids = append(ids, issue.ComponentId)
+		}
+	}
+
+	slices.Sort(ids)
+	return ids
+}
+
+func TestValidateFlowRecursiveSubflows(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []*model.FlowGroup
+		want   []int64
+	}{
+		{"no calls", []*model.FlowGroup{subflowGroup(1), subflowGroup(2)}, []int64{}},
+		{"call", []*model.FlowGroup{subflowGroup(1, 2), subflowGroup(2)}, []int64{}},
+		{"self call", []*model.FlowGroup{subflowGroup(1, 1)}, []int64{10}},
+		{"cycle", []*model.FlowGroup{subflowGroup(1, 2), subflowGroup(2, 3), subflowGroup(3, 1)}, []int64{10, 20, 30}},
+		{"call of a cycle", []*model.FlowGroup{subflowGroup(1, 2), subflowGroup(2, 3), subflowGroup(3, 2)}, []int64{20, 30}},
+		{"same group twice", []*model.FlowGroup{subflowGroup(1, 2, 2), subflowGroup(2)}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		f := &model.Flow{Groups: tt.groups}
+		if got := recursiveCalls(f); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: recursive calls %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package graph
+
+import (
+	"slices"
+
+	"github.com/botscubes/bot-components/components"
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+// Variables read and written by the component
+type ComponentVariables struct {
+	GroupId     int64                `json:"groupId"`
+	ComponentId int64                `json:"componentId"`
+	Reads       []*model.VariableRef `json:"reads"`
+	Write       string               `json:"write,omitempty"`
+}
+
+// Variable read by the component that no path to the component writes
+type VariableWarning struct {
+	GroupId     int64  `json:"groupId"`
+	ComponentId int64  `json:"componentId"`
+	Field       string `json:"field"`
+	Variable    string `json:"variable"`
+	Message     string `json:"message"`
+}
+
+type VariablesReport struct {
+	// Names of all variables of the flow
+	Variables  []string              `json:"variables"`
+	Components []*ComponentVariables `json:"components"`
+	Warnings   []*VariableWarning    `json:"warnings"`
+}
+
+// Analysis of the variables of the whole flow. The walk starts at the start component,
+// a sub-flow component leads to the entry of the called group and writes every
+// variable the group may write. A variable may be written before the component
+// if some path from the start to the component writes it.
+func AnalyzeVariables(f *model.Flow) *VariablesReport {
+	report := &VariablesReport{
+		Variables:  []string{},
+		Components: []*ComponentVariables{},
+		Warnings:   []*VariableWarning{},
+	}
+
+	comps := make(map[int64]*model.Component)
+	groupOf := make(map[int64]int64)
+	groups := make(map[int64]*model.FlowGroup)
+	reads := make(map[int64][]*model.VariableRef)
+	writes := make(map[int64]string)
+	names := make(map[string]bool)
+
+	for _, g := range f.Groups {
+		groups[g.Id] = g
+		for _, c := range g.Components {
+			comps[c.Id] = c
+			groupOf[c.Id] = g.Id
+			reads[c.Id] = c.VariableReads()
+			writes[c.Id] = c.VariableWrite()
+
+			for _, r := range reads[c.Id] {
+				names[r.Name] = true
+			}
+			if writes[c.Id] != "" {
+				names[writes[c.Id]] = true
+			}
+
+			if len(reads[c.Id]) > 0 || writes[c.Id] != "" {
+				report.Components = append(report.Components, &ComponentVariables{
+					GroupId:     g.Id,
+					ComponentId: c.Id,
+					Reads:       reads[c.Id],
+					Write:       writes[c.Id],
+				})
+			}
+		}
+	}
+
+	for name := range names {
+		report.Variables = append(report.Variables, name)
+	}
+	slices.Sort(report.Variables)
+
+	groupWrites := subflowWrites(f, writes)
+
+	// written variables after the component
+	written := func(c *model.Component, before map[string]bool) map[string]bool {
+		after := make(map[string]bool, len(before)+1)
+		for name := range before {
+			after[name] = true
+		}
+		if writes[c.Id] != "" {
+			after[writes[c.Id]] = true
+		}
+		if groupId := c.SubflowGroupId(); groupId != 0 {
+			for name := range groupWrites[groupId] {
+				after[name] = true
+			}
+		}
+		return after
+	}
+
+	next := func(c *model.Component) []int64 {
+		ids := []int64{}
+		for _, name := range OutputNames(c) {
+			if _, ok := comps[c.Outputs[name]]; ok {
+				ids = append(ids, c.Outputs[name])
+			}
+		}
+		if g, ok := groups[c.SubflowGroupId()]; ok && g.EntryComponentId != nil {
+			ids = append(ids, *g.EntryComponentId)
+		}
+		return ids
+	}
+
+	_, start := f.Start()
+	if start == nil {
+		return report
+	}
+
+	// variables that may be written before the component
+	in := map[int64]map[string]bool{start.Id: {}}
+	queue := []int64{start.Id}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		out := written(comps[id], in[id])
+		for _, nextId := range next(comps[id]) {
+			changed := in[nextId] == nil
+			if changed {
+				in[nextId] = make(map[string]bool)
+			}
+			for name := range out {
+				if !in[nextId][name] {
+					in[nextId][name] = true
+					changed = true
+				}
+			}
+			if changed {
+				queue = append(queue, nextId)
+			}
+		}
+	}
+
+	for _, g := range f.Groups {
+		for _, c := range g.Components {
+			// unreachable components are reported by the group analysis
+			before, reachable := in[c.Id]
+			if !reachable || c.Type == components.TypeStart {
+				continue
+			}
+
+			for _, r := range reads[c.Id] {
+				if !before[r.Name] {
+					report.Warnings = append(report.Warnings, &VariableWarning{
+						GroupId:     groupOf[c.Id],
+						ComponentId: c.Id,
+						Field:       r.Field,
+						Variable:    r.Name,
+						Message:     "The variable is read before it is written",
+					})
+				}
+			}
+		}
+	}
+
+	return report
+}
+
+// Variables that the call of the group may write, including nested sub-flows
+func subflowWrites(f *model.Flow, writes map[int64]string) map[int64]map[string]bool {
+	groupWrites := make(map[int64]map[string]bool, len(f.Groups))
+	for _, g := range f.Groups {
+		groupWrites[g.Id] = make(map[string]bool)
+		for _, c := range g.Components {
+			if writes[c.Id] != "" {
+				groupWrites[g.Id][writes[c.Id]] = true
+			}
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, g := range f.Groups {
+			for _, c := range g.Components {
+				for name := range groupWrites[c.SubflowGroupId()] {
+					if !groupWrites[g.Id][name] {
+						groupWrites[g.Id][name] = true
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	return groupWrites
+}
+
+// Warnings of the variables of one group, without loading the other groups. Only the
+// main group is walked, from the start component: the variables written before the entry
+// of another group depend on its callers. A sub-flow call may write any variable, the
+// components after it are not checked. The warnings are a subset of the warnings of
+// AnalyzeVariables for the group.
+func GroupVariableWarnings(groupId int64, comps []*model.Component) []*VariableWarning {
+	warnings := []*VariableWarning{}
+
+	byId := make(map[int64]*model.Component, len(comps))
+	var start *model.Component
+	for _, c := range comps {
+		byId[c.Id] = c
+		if c.Type == components.TypeStart {
+			start = c
+		}
+	}
+
+	if start == nil {
+		return warnings
+	}
+
+	// variables that may be written before the component, the components after
+	// a sub-flow call are unknown
+	in := map[int64]map[string]bool{start.Id: {}}
+	unknown := make(map[int64]bool)
+	queue := []int64{start.Id}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		c := byId[id]
+
+		out := make(map[string]bool, len(in[id])+1)
+		for name := range in[id] {
+			out[name] = true
+		}
+		if name := c.VariableWrite(); name != "" {
+			out[name] = true
+		}
+		outUnknown := unknown[id] || c.SubflowGroupId() != 0
+
+		for _, name := range OutputNames(c) {
+			nextId := c.Outputs[name]
+			if _, ok := byId[nextId]; !ok {
+				continue
+			}
+
+			changed := in[nextId] == nil
+			if changed {
+				in[nextId] = make(map[string]bool)
+			}
+			if outUnknown && !unknown[nextId] {
+				unknown[nextId] = true
+				changed = true
+			}
+			for name := range out {
+				if !in[nextId][name] {
+					in[nextId][name] = true
+					changed = true
+				}
+			}
+			if changed {
+				queue = append(queue, nextId)
+			}
+		}
+	}
+
+	for _, c := range comps {
+		before, reachable := in[c.Id]
+		if !reachable || unknown[c.Id] || c.Type == components.TypeStart {
+			continue
+		}
+
+		for _, r := range c.VariableReads() {
+			if !before[r.Name] {
+				warnings = append(warnings, &VariableWarning{
+					GroupId:     groupId,
+					ComponentId: c.Id,
+					Field:       r.Field,
+					Variable:    r.Name,
+					Message:     "The variable is read before it is written",
+				})
+			}
+		}
+	}
+
+	return warnings
+}
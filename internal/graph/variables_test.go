@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/botscubes/bot-components/components"
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+func variableComponent(id int64, ctype string, path string, data map[string]any, next int64) *model.Component {
+	c := &model.Component{Id: id, Data: data, Outputs: map[string]int64{}}
+	c.Type = ctype
+	c.Path = path
+	if next != 0 {
+		c.Outputs["nextComponentId"] = next
+	}
+
+	return c
+}
+
+func warnedComponents(warnings []*VariableWarning) []int64 {
+	ids := []int64{}
+	for _, w := range warnings {
+		ids = append(ids, w.ComponentId)
+	}
+
+	slices.Sort(ids)
+	return ids
+}
+
+func TestGroupVariableWarnings(t *testing.T) {
+	text := map[string]any{"text": "Hi, ${name}"}
+	main := []*model.Component{
+		variableComponent(1, components.TypeStart, "", nil, 2),
+		// read before the write
+		variableComponent(2, components.TypeMessage, "", text, 3),
+		variableComponent(3, components.TypeFormat, "name", map[string]any{"formatString": "Bob"}, 4),
+		variableComponent(4, components.TypeMessage, "", text, 5),
+		variableComponent(5, model.TypeSubflow, "", map[string]any{"groupId": float64(2)}, 6),
+		// the sub-flow may write the variable
+		variableComponent(6, components.TypeMessage, "", map[string]any{"text": "${city}"}, 0),
+	}
+
+	if got := warnedComponents(GroupVariableWarnings(1, main)); !slices.Equal(got, []int64{2}) {
+		t.Errorf("main group: warned components = %v, want [2]", got)
+	}
+
+	// the variables written by the callers of the group are unknown
+	called := []*model.Component{variableComponent(20, components.TypeMessage, "", map[string]any{"text": "${city}"}, 0)}
+	if got := GroupVariableWarnings(2, called); len(got) != 0 {
+		t.Errorf("called group: warnings = %v, want none", warnedComponents(got))
+	}
+
+	// the warnings of the group are a subset of the warnings of the whole flow
+	entry := int64(20)
+	flow := &model.Flow{Groups: []*model.FlowGroup{
+		{Id: 1, Components: main},
+		{Id: 2, EntryComponentId: &entry, Components: called},
+	}}
+
+	if got := warnedComponents(AnalyzeVariables(flow).Warnings); !slices.Equal(got, []int64{2, 6, 20}) {
+		t.Errorf("flow: warned components = %v, want [2 6 20]", got)
+	}
+}
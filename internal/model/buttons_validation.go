@@ -0,0 +1,78 @@
+package model
+
+import (
+	"slices"
+	"strconv"
+)
+
+// Keyboard of the buttons component. The data key "buttons" holds the buttons by index,
+// the index is the name of the output of the button and does not change when
+// other buttons are added or removed:
+//
+//	{"0": {"text": "Yes"}, "1": {"text": "No"}, "2": {"text": "Back"}}
+//
+// The worker places the buttons one per row.
+
+const (
+	maxButtonTextLen = 64
+	maxButtons       = 100
+)
+
+var buttonsSchema = map[string]any{
+	"maxProperties": maxButtons,
+	"propertyNames": map[string]any{"pattern": "^(0|[1-9][0-9]*)$"},
+	"additionalProperties": map[string]any{
+		"type":     "object",
+		"required": []string{"text"},
+		"properties": map[string]any{
+			"text": map[string]any{"type": "string", "minLength": 1, "maxLength": maxButtonTextLen},
+		},
+		"additionalProperties": false,
+	},
+}
+
+// Indexes of the buttons in ascending order, the data is expected to be valid
+func ButtonIndexes(data map[string]any) []string {
+	buttons, _ := data["buttons"].(map[string]any)
+	indexes := make([]int, 0, len(buttons))
+	for key := range buttons {
+		if i, err := strconv.Atoi(key); err == nil {
+			indexes = append(indexes, i)
+		}
+	}
+
+	slices.Sort(indexes)
+
+	keys := make([]string, len(indexes))
+	for i, index := range indexes {
+		keys[i] = strconv.Itoa(index)
+	}
+
+	return keys
+}
+
+// The numeric output of the component leads from an existing button
+func HasButton(data map[string]any, outputName string) bool {
+	return slices.Contains(ButtonIndexes(data), outputName)
+}
+
+// Checks of the keyboard that cannot be expressed by the schema:
+// the pressed button is found by its text, so the texts must be unique.
+func validateButtons(data map[string]any) []*DataError {
+	errs := []*DataError{}
+	buttons, _ := data["buttons"].(map[string]any)
+
+	texts := make(map[string]bool)
+	for _, key := range ButtonIndexes(data) {
+		button, _ := buttons[key].(map[string]any)
+		pointer := "/buttons/" + key
+
+		text, _ := button["text"].(string)
+		if texts[text] {
+			errs = append(errs, &DataError{Pointer: pointer + "/text", Message: "Duplicate button text"})
+		}
+		texts[text] = true
+	}
+
+	return errs
+}
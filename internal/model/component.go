@@ -7,6 +7,10 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// Sub-flow call: the flow continues at the entry component of another group
+// and returns to the outputs of the component when the group flow ends.
+const TypeSubflow components.ComponentType = "subflow"
+
 type ComponentStatus int
 
 var (
@@ -21,6 +25,8 @@ type Component struct {
 	ConnectionPoints map[string]*ConnectionPoint `json:"connectionPoints"`
 	Outputs          map[string]int64            `json:"outputs"`
 	Data             map[string]any              `json:"data"`
+	// The debugger pauses before the component, it is not a part of the flow
+	Breakpoint bool `json:"breakpoint,omitempty"`
 }
 
 type ComponentData struct {
@@ -70,6 +76,67 @@ func (c *Component) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, &c)
 }
 
+// Replace ids of the linked components using the ids map (old id -> new id).
+// Outputs and connection points of components missing from the map are dropped.
+func (c *Component) RemapIds(ids map[int64]int64) {
+	if id, ok := ids[c.Id]; ok {
+		c.Id = id
+	}
+
+	outputs := make(map[string]int64, len(c.Outputs))
+	for name, targetId := range c.Outputs {
+		if id, ok := ids[targetId]; ok {
+			outputs[name] = id
+		}
+	}
+	c.Outputs = outputs
+
+	points := make(map[string]*ConnectionPoint, len(c.ConnectionPoints))
+	for _, p := range c.ConnectionPoints {
+		if p == nil || p.SourceComponentId == nil || p.SourcePointName == nil {
+			continue
+		}
+
+		id, ok := ids[*p.SourceComponentId]
+		if !ok {
+			continue
+		}
+
+		point := *p
+		point.SourceComponentId = &id
+		points[ConnectionPointKey(id, *p.SourcePointName)] = &point
+	}
+	c.ConnectionPoints = points
+}
+
+// Id of the group called by the sub-flow component, 0 if it is not set
+func (c *Component) SubflowGroupId() int64 {
+	if c.Type != TypeSubflow {
+		return 0
+	}
+
+	v, ok := c.Data["groupId"].(float64)
+	if !ok {
+		return 0
+	}
+
+	return int64(v)
+}
+
+// Replace the group called by the sub-flow component using the map of old to new group ids
+func (c *Component) RemapSubflowGroupId(groupIds map[int64]int64) {
+	groupId := c.SubflowGroupId()
+	if groupId == 0 {
+		return
+	}
+
+	if newId, ok := groupIds[groupId]; ok {
+		c.Data["groupId"] = float64(newId)
+	} else {
+		delete(c.Data, "groupId")
+	}
+}
+
 type AddComponentReq struct {
 	Type     components.ComponentType `json:"type"`
 	Position *Point                   `json:"position"`
@@ -83,6 +150,37 @@ type DelSetComponentsReq struct {
 	Data *[]int64 `json:"data"`
 }
 
+// Offset of the copies if the request has no offset, the copies do not cover the source components
+const DefaultCopyOffset = 40
+
+type CopyComponentsReq struct {
+	SourceBotId *int64   `json:"sourceBotId"`
+	Ids         *[]int64 `json:"ids"`
+	Offset      *Point   `json:"offset"`
+}
+
+type MoveComponentsReq struct {
+	Ids              *[]int64 `json:"ids"`
+	GroupId          *int64   `json:"groupId"`
+	BreakConnections bool     `json:"breakConnections"`
+	ResetEntry       bool     `json:"resetEntry"`
+}
+
+type ComponentPosition struct {
+	Id *int64 `json:"id"`
+	Point
+}
+
+type SetPositionsReq struct {
+	Data *[]*ComponentPosition `json:"data"`
+	// Arrange all components of the group instead of the given positions
+	Layout bool `json:"layout"`
+}
+
+type SetBreakpointReq struct {
+	Breakpoint *bool `json:"breakpoint"`
+}
+
 type UpdComponentReq struct {
 	Data     *ComponentData `json:"data"`
 	Position *Point         `json:"position"`
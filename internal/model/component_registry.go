@@ -0,0 +1,202 @@
+package model
+
+import (
+	"github.com/botscubes/bot-components/components"
+)
+
+// Registry of the component types: the single description of the data fields
+// and outputs of every type. The registry only describes the rules: the data is
+// checked by the schemas built from it (component_schema.go), the buttons keyboard
+// and the expressions, the editor forms are built from it too. The stored data is
+// not migrated to the stricter rules, see docs/changelog.md.
+
+const (
+	FieldString  = "string"
+	FieldInteger = "integer"
+	FieldObject  = "object"
+)
+
+// Usage of the component path
+const (
+	// the result of the component is written to the variable at the path
+	PathResult = "result"
+	// the component reads the variable at the path
+	PathSource = "source"
+)
+
+type DataFieldInfo struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Required  bool     `json:"required"`
+	MaxLength int      `json:"maxLength,omitempty"`
+	Minimum   *int64   `json:"minimum,omitempty"`
+	Enum      []string `json:"enum,omitempty"`
+	Format    string   `json:"format,omitempty"`
+	// Syntax of the expression in the string value
+	Syntax string `json:"syntax,omitempty"`
+	// JSON Schema of the structure of an object value
+	Schema map[string]any `json:"schema,omitempty"`
+}
+
+type ComponentTypeInfo struct {
+	Type components.ComponentType `json:"type"`
+	// Components of the type can be added by the user
+	Addable bool             `json:"addable"`
+	Data    []*DataFieldInfo `json:"data"`
+	Outputs []string         `json:"outputs"`
+	// Besides Outputs the component has outputs with numeric names (one per button)
+	NumericOutputs bool `json:"numericOutputs"`
+	// Usage of the component path, empty if the path is not used
+	PathUsage string `json:"pathUsage,omitempty"`
+	// Checks of the valid data beyond the schema
+	validate func(data map[string]any) []*DataError
+}
+
+var minGroupId int64 = 1
+
+// Outputs of most component types
+var defaultOutputs = []string{"nextComponentId", "idIfError"}
+
+var HTTPMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+var ComponentTypes = []*ComponentTypeInfo{
+	{
+		Type:    components.TypeStart,
+		Data:    []*DataFieldInfo{},
+		Outputs: []string{"nextComponentId"},
+	},
+	{
+		Type:    components.TypeFormat,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "formatString", Type: FieldString, Required: true, Syntax: SyntaxFormat},
+		},
+		Outputs:   defaultOutputs,
+		PathUsage: PathResult,
+	},
+	{
+		Type:    components.TypeCondition,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "expression", Type: FieldString, Required: true, Syntax: SyntaxPath},
+		},
+		Outputs: []string{"nextComponentId", "idIfFalse", "idIfError"},
+	},
+	{
+		Type:    components.TypeMessage,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "text", Type: FieldString, Required: true, MaxLength: maxMessageLen, Syntax: SyntaxFormat},
+		},
+		Outputs: defaultOutputs,
+	},
+	{
+		Type:      components.TypeTextInput,
+		Addable:   true,
+		Data:      []*DataFieldInfo{},
+		Outputs:   defaultOutputs,
+		PathUsage: PathResult,
+	},
+	{
+		Type:    components.TypeButtons,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "text", Type: FieldString, Required: true, MaxLength: maxMessageLen},
+			{Name: "buttons", Type: FieldObject, Required: true, Schema: buttonsSchema},
+		},
+		Outputs:        []string{"idIfError"},
+		NumericOutputs: true,
+		PathUsage:      PathResult,
+		validate:       validateButtons,
+	},
+	{
+		Type:    components.TypeCode,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "code", Type: FieldString, Required: true, MaxLength: maxCodeLen, Syntax: SyntaxCode},
+		},
+		Outputs:   defaultOutputs,
+		PathUsage: PathResult,
+	},
+	{
+		Type:    components.TypeToInt,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "source", Type: FieldString, Required: true, Syntax: SyntaxPath},
+		},
+		Outputs:   defaultOutputs,
+		PathUsage: PathResult,
+	},
+	{
+		Type:    components.TypeMove,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "source", Type: FieldString, Required: true, Syntax: SyntaxPath},
+		},
+		Outputs:   defaultOutputs,
+		PathUsage: PathResult,
+	},
+	{
+		Type:    components.TypeHTTP,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "url", Type: FieldString, Required: true, Format: formatHTTPURL, Syntax: SyntaxFormat},
+			{Name: "method", Type: FieldString, Required: true, Enum: HTTPMethods},
+			{Name: "body", Type: FieldString, Syntax: SyntaxFormat},
+			{Name: "header", Type: FieldString},
+		},
+		Outputs:   defaultOutputs,
+		PathUsage: PathResult,
+	},
+	{
+		Type:    components.TypeFromJSON,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "json", Type: FieldString, Required: true, Syntax: SyntaxFormat},
+		},
+		Outputs:   defaultOutputs,
+		PathUsage: PathResult,
+	},
+	{
+		Type:    components.TypePhoto,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "name", Type: FieldString, Required: true, Syntax: SyntaxFormat},
+		},
+		Outputs:   defaultOutputs,
+		PathUsage: PathSource,
+	},
+	{
+		Type:    TypeSubflow,
+		Addable: true,
+		Data: []*DataFieldInfo{
+			{Name: "groupId", Type: FieldInteger, Required: true, Minimum: &minGroupId},
+		},
+		Outputs: defaultOutputs,
+	},
+}
+
+var componentTypesByName = func() map[string]*ComponentTypeInfo {
+	m := make(map[string]*ComponentTypeInfo, len(ComponentTypes))
+	for _, t := range ComponentTypes {
+		m[t.Type] = t
+	}
+
+	return m
+}()
+
+// Description of the component type, nil if the type is unknown
+func GetComponentTypeInfo(t components.ComponentType) *ComponentTypeInfo {
+	return componentTypesByName[t]
+}
+
+// Description of the data field of the component type, nil if there is no such field
+func (t *ComponentTypeInfo) Field(name string) *DataFieldInfo {
+	for _, f := range t.Data {
+		if f.Name == name {
+			return f
+		}
+	}
+
+	return nil
+}
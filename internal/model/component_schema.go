@@ -0,0 +1,253 @@
+package model
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// JSON Schemas of the component data built from the registry.
+// A partial schema checks the values of the given keys (data is saved key by key),
+// a complete schema also requires every required key with a non-empty value.
+
+const formatHTTPURL = "http-url"
+
+var templateRegexp = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// HTTP(S) URL that may contain ${...} substitutions
+func isHTTPURL(v any) bool {
+	s, ok := v.(string)
+	if !ok {
+		return true
+	}
+
+	// scheme and host are substituted at runtime
+	if strings.HasPrefix(s, "${") {
+		return true
+	}
+
+	u, err := url.Parse(templateRegexp.ReplaceAllString(s, "x"))
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// JSON Schema of the data field
+func (f *DataFieldInfo) schema(complete bool) map[string]any {
+	s := map[string]any{}
+	for k, v := range f.Schema {
+		s[k] = v
+	}
+
+	s["type"] = f.Type
+	if f.MaxLength > 0 {
+		s["maxLength"] = f.MaxLength
+	}
+	if f.Minimum != nil {
+		s["minimum"] = *f.Minimum
+	}
+	if len(f.Enum) > 0 {
+		s["enum"] = f.Enum
+	}
+	if f.Format != "" {
+		s["format"] = f.Format
+	}
+	if complete && f.Required && f.Type == FieldString {
+		s["minLength"] = 1
+	}
+
+	return s
+}
+
+// JSON Schema of the component data
+func (t *ComponentTypeInfo) Schema(complete bool) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for _, f := range t.Data {
+		properties[f.Name] = f.schema(complete)
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+
+	s := map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if complete && len(required) > 0 {
+		s["required"] = required
+	}
+
+	return s
+}
+
+type componentSchemas struct {
+	partial  *jsonschema.Schema
+	complete *jsonschema.Schema
+}
+
+// Compiled schemas by component type
+var schemas = func() map[string]*componentSchemas {
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft2020
+	c.AssertFormat = true
+	c.Formats[formatHTTPURL] = isHTTPURL
+
+	compile := func(name string, s map[string]any) *jsonschema.Schema {
+		data, err := json.Marshal(s)
+		if err != nil {
+			panic(err)
+		}
+
+		if err := c.AddResource(name, bytes.NewReader(data)); err != nil {
+			panic(err)
+		}
+
+		return c.MustCompile(name)
+	}
+
+	m := make(map[string]*componentSchemas, len(ComponentTypes))
+	for _, t := range ComponentTypes {
+		m[t.Type] = &componentSchemas{
+			partial:  compile("component/"+t.Type+"/partial.json", t.Schema(false)),
+			complete: compile("component/"+t.Type+"/complete.json", t.Schema(true)),
+		}
+	}
+
+	return m
+}()
+
+// Failed field of the component data
+type DataError struct {
+	Pointer string `json:"pointer"` // JSON pointer of the field within the data
+	Message string `json:"message"`
+	// Position of the syntax error in the expression
+	Line   int `json:"line,omitempty"`
+	Column int `json:"column,omitempty"`
+}
+
+// Validate the component data with the schema of its type, every failed field is reported
+func ValidateComponentData(ctype string, data map[string]any, complete bool) []*DataError {
+	errs := []*DataError{}
+
+	info := GetComponentTypeInfo(ctype)
+	if info == nil {
+		for _, key := range sortedKeys(data) {
+			errs = append(errs, &DataError{Pointer: "/" + key, Message: "Non-existent param"})
+		}
+		return errs
+	}
+
+	s := schemas[ctype].partial
+	if complete {
+		s = schemas[ctype].complete
+	}
+
+	// the data must be a JSON value for the validator
+	var value any = map[string]any{}
+	if data != nil {
+		value = data
+	}
+
+	err := s.Validate(value)
+	if err == nil {
+		errs = expressionErrors(info, data)
+		if info.validate != nil {
+			errs = append(errs, info.validate(data)...)
+		}
+		return errs
+	}
+
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return append(errs, &DataError{Pointer: "", Message: err.Error()})
+	}
+
+	for _, cause := range leafErrors(ve) {
+		keyword := cause.KeywordLocation[strings.LastIndex(cause.KeywordLocation, "/")+1:]
+
+		// errors of the data object itself are reported for every key
+		if cause.InstanceLocation == "" && keyword == "additionalProperties" {
+			for _, key := range sortedKeys(data) {
+				if info.Field(key) == nil {
+					errs = append(errs, &DataError{Pointer: "/" + key, Message: "Non-existent param"})
+				}
+			}
+			continue
+		}
+
+		if cause.InstanceLocation == "" && keyword == "required" {
+			for _, f := range info.Data {
+				if _, ok := data[f.Name]; f.Required && !ok {
+					errs = append(errs, &DataError{Pointer: "/" + f.Name, Message: "Required parameter is missing"})
+				}
+			}
+			continue
+		}
+
+		errs = append(errs, &DataError{Pointer: cause.InstanceLocation, Message: cause.Message})
+	}
+
+	slices.SortStableFunc(errs, func(a, b *DataError) int {
+		return strings.Compare(a.Pointer, b.Pointer)
+	})
+
+	return errs
+}
+
+// Syntax errors of the expression fields of the valid data
+func expressionErrors(info *ComponentTypeInfo, data map[string]any) []*DataError {
+	errs := []*DataError{}
+	for _, f := range info.Data {
+		// empty expressions are reported as missing data
+		expr, _ := data[f.Name].(string)
+		if f.Syntax == "" || expr == "" {
+			continue
+		}
+
+		for _, err := range CheckExpression(f.Syntax, expr) {
+			errs = append(errs, &DataError{
+				Pointer: "/" + f.Name,
+				Message: err.Message,
+				Line:    err.Line,
+				Column:  err.Column,
+			})
+		}
+	}
+
+	return errs
+}
+
+// Errors without causes, in the order of the schema
+func leafErrors(ve *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(ve.Causes) == 0 {
+		return []*jsonschema.ValidationError{ve}
+	}
+
+	leaves := []*jsonschema.ValidationError{}
+	for _, cause := range ve.Causes {
+		leaves = append(leaves, leafErrors(cause)...)
+	}
+
+	return leaves
+}
+
+func sortedKeys(data map[string]any) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
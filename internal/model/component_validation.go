@@ -53,18 +53,14 @@ func (p *Point) Validate() *se.ServiceError {
 	return nil
 }
 
+// Check that components of the type can be added by the user
+func isAddableComponentType(t components.ComponentType) bool {
+	info := GetComponentTypeInfo(t)
+	return info != nil && info.Addable
+}
+
 func (r *AddComponentReq) Validate() *se.ServiceError {
-	if r.Type != components.TypeFormat &&
-		r.Type != components.TypeCondition &&
-		r.Type != components.TypeMessage &&
-		r.Type != components.TypeTextInput &&
-		r.Type != components.TypeCode &&
-		r.Type != components.TypeButtons &&
-		r.Type != components.TypeToInt &&
-		r.Type != components.TypeMove &&
-		r.Type != components.TypeHTTP &&
-		r.Type != components.TypeFromJSON &&
-		r.Type != components.TypePhoto {
+	if !isAddableComponentType(r.Type) {
 		return e.InvalidParam("type: " + r.Type)
 	}
 
@@ -103,6 +99,110 @@ func (r *DelSetComponentsReq) Validate() *se.ServiceError {
 	return nil
 }
 
+func (r *CopyComponentsReq) Validate() *se.ServiceError {
+	if r.Ids == nil {
+		return e.MissingParam("ids")
+	}
+
+	if len(*r.Ids) == 0 {
+		return e.InvalidParam("ids")
+	}
+
+	for _, v := range *r.Ids {
+		if v == config.MainComponentId {
+			return e.ErrMainComponent
+		}
+	}
+
+	if r.Offset != nil {
+		return r.Offset.Validate()
+	}
+
+	return nil
+}
+
+func (r *MoveComponentsReq) Validate() *se.ServiceError {
+	if r.Ids == nil {
+		return e.MissingParam("ids")
+	}
+
+	if len(*r.Ids) == 0 {
+		return e.InvalidParam("ids")
+	}
+
+	// the start component stays in the main group
+	for _, v := range *r.Ids {
+		if v == config.MainComponentId {
+			return e.ErrMainComponent
+		}
+	}
+
+	if r.GroupId == nil {
+		return e.MissingParam("groupId")
+	}
+
+	return nil
+}
+
+func (r *SetPositionsReq) Validate() *se.ServiceError {
+	// the positions are computed by the layout
+	if r.Layout {
+		if r.Data != nil {
+			return e.InvalidParam("data: not allowed with layout")
+		}
+
+		return nil
+	}
+
+	if r.Data == nil {
+		return e.MissingParam("data")
+	}
+
+	if len(*r.Data) == 0 {
+		return e.InvalidParam("data")
+	}
+
+	ids := make(map[int64]bool, len(*r.Data))
+	for _, p := range *r.Data {
+		if p == nil {
+			return e.InvalidParam("data")
+		}
+
+		if p.Id == nil {
+			return e.MissingParam("id")
+		}
+
+		if ids[*p.Id] {
+			return e.InvalidParam("id")
+		}
+		ids[*p.Id] = true
+
+		if err := p.Point.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Ids of the components in the request
+func (r *SetPositionsReq) Ids() []int64 {
+	ids := make([]int64, 0, len(*r.Data))
+	for _, p := range *r.Data {
+		ids = append(ids, *p.Id)
+	}
+
+	return ids
+}
+
+func (r *SetBreakpointReq) Validate() *se.ServiceError {
+	if r.Breakpoint == nil {
+		return e.MissingParam("breakpoint")
+	}
+
+	return nil
+}
+
 func (r *UpdComponentReq) Validate() *se.ServiceError {
 	if r.Data == nil && r.Position == nil {
 		return e.ErrBadRequest
@@ -1,5 +1,7 @@
 package model
 
+import "strconv"
+
 type SourceConnectionPoint struct {
 	SourceComponentId *int64  `json:"sourceComponentId"`
 	SourcePointName   *string `json:"sourcePointName"`
@@ -15,3 +17,8 @@ type Connection struct {
 
 	TargetComponentId *int64 `json:"targetComponentId"`
 }
+
+// Key of the connection point in the "connection_points" of the target component
+func ConnectionPointKey(sourceComponentId int64, sourcePointName string) string {
+	return strconv.FormatInt(sourceComponentId, 10) + " " + sourcePointName
+}
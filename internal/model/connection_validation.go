@@ -13,11 +13,11 @@ func (c *SourceConnectionPoint) Validate(componentType string) *se.ServiceError
 	if *c.SourceComponentId < 0 {
 		return e.InvalidParam("sourceComponentId must not be negative")
 	}
-	validate, ok := SpecificComponentOutputValidation[componentType]
-	if !ok {
+	info := GetComponentTypeInfo(componentType)
+	if info == nil {
 		return e.ErrValidation
 	}
-	se := validate(*c.SourcePointName)
+	se := info.ValidateOutput(*c.SourcePointName)
 	if se != nil {
 		return se
 	}
@@ -0,0 +1,41 @@
+package model
+
+import "github.com/goccy/go-json"
+
+type NewDebugSessionReq struct {
+	// Inputs of the user, they are delivered when the flow waits for an input
+	Inputs []*SimulationInput `json:"inputs"`
+	// Initial variables of the context
+	Variables map[string]any `json:"variables"`
+	// Results of the http and code components by component id, they are not executed
+	Mocks map[string]any `json:"mocks"`
+}
+
+// Command of the debug session (continue, step), the inputs are added to the queue before it
+type DebugCommandReq struct {
+	Inputs []*SimulationInput `json:"inputs"`
+}
+
+// Debug session saved between the commands
+type DebugSession struct {
+	Id string `json:"id"`
+	// Flow of the bot at the creation of the session
+	Flow  *Flow            `json:"flow"`
+	State *SimulationState `json:"state"`
+	// Results of the http and code components by component id
+	Mocks map[int64]any `json:"mocks"`
+	// Queue of the inputs
+	Inputs []*SimulationInput `json:"inputs"`
+	// The run is paused before the component the user is on
+	Paused bool `json:"paused"`
+}
+
+// Encode debug session struct to binary format (for redis)
+func (s *DebugSession) MarshalBinary() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// Decode debug session from binary format to struct (for redis)
+func (s *DebugSession) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &s)
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+func (r *NewDebugSessionReq) Validate() *se.ServiceError {
+	if err := validateInputs(r.Inputs); err != nil {
+		return err
+	}
+
+	return validateMocks(r.Mocks)
+}
+
+// Mocks by component id, the request must be valid
+func (r *NewDebugSessionReq) MockResults() map[int64]any {
+	return mockResults(r.Mocks)
+}
+
+func (r *DebugCommandReq) Validate() *se.ServiceError {
+	return validateInputs(r.Inputs)
+}
@@ -0,0 +1,30 @@
+package model
+
+// Syntax of the expression fields of the components, the expressions are checked
+// the same way the worker executes them.
+const (
+	// bql code, ${...} substitutions are made before the execution. The code is
+	// parsed with the copy of the bql parser (internal/bql), never executed.
+	SyntaxCode = "code"
+	// path of the context variable or true/false
+	SyntaxPath = "path"
+	// text with ${...} substitutions of the context variables
+	SyntaxFormat = "format"
+)
+
+// Syntax error of the expression
+type ExpressionError struct {
+	// Position of the error (from 1), 0 if the position is unknown
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+type CheckExpressionReq struct {
+	Syntax     *string `json:"syntax"`
+	Expression *string `json:"expression"`
+}
+
+type CheckExpressionRes struct {
+	Errors []*ExpressionError `json:"errors"`
+}
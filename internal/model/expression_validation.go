@@ -0,0 +1,202 @@
+package model
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/botscubes/bot-components/context"
+	"github.com/botscubes/bot-components/format"
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	"github.com/botscubes/bot-service/internal/bql/lexer"
+	"github.com/botscubes/bot-service/internal/bql/parser"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+// Max length of the code: the parser is recursive, the nesting of the code is limited by its length
+const maxCodeLen = 16384
+
+// Placeholder of the ${...} substitution in the parsed code, the value of the variable
+// is known only at runtime
+const substitutionPlaceholder = "_"
+
+// Check the expression of the given syntax
+func CheckExpression(syntax string, expr string) []*ExpressionError {
+	var errs []*ExpressionError
+	switch syntax {
+	case SyntaxCode:
+		errs = checkCode(expr)
+	case SyntaxFormat:
+		errs = checkFormat(expr)
+	case SyntaxPath:
+		errs = checkPath(expr)
+	}
+
+	if errs == nil {
+		return []*ExpressionError{}
+	}
+
+	return errs
+}
+
+func (r *CheckExpressionReq) Validate() *se.ServiceError {
+	if r.Syntax == nil {
+		return e.MissingParam("syntax")
+	}
+
+	if *r.Syntax != SyntaxCode && *r.Syntax != SyntaxPath && *r.Syntax != SyntaxFormat {
+		return e.InvalidParam("syntax")
+	}
+
+	if r.Expression == nil {
+		return e.MissingParam("expression")
+	}
+
+	return nil
+}
+
+func checkPath(expr string) []*ExpressionError {
+	expr = strings.TrimSpace(expr)
+	if expr == "true" || expr == "false" {
+		return nil
+	}
+
+	if err := context.CheckPath(expr); err != nil {
+		return []*ExpressionError{{Message: err.Error()}}
+	}
+
+	return nil
+}
+
+// Check of the text with ${...} substitutions, the error is reported at the escape
+// sequence or the substitution it is found in.
+func checkFormat(s string) []*ExpressionError {
+	err := format.CheckFormatString(s)
+	if err == nil {
+		return nil
+	}
+
+	line, column := 0, 0
+	if i := formatErrorIndex([]rune(s)); i >= 0 {
+		line, column = runePosition(s, i)
+	}
+
+	return []*ExpressionError{{Line: line, Column: column, Message: err.Error()}}
+}
+
+// Check of the code: the substitutions and the bql syntax. The code is parsed with
+// the copy of the bql parser and never executed.
+func checkCode(s string) []*ExpressionError {
+	if n := utf8.RuneCountInString(s); n > maxCodeLen {
+		return []*ExpressionError{{Message: "The code is longer than " + strconv.Itoa(maxCodeLen) + " characters"}}
+	}
+
+	if errs := checkFormat(s); errs != nil {
+		return errs
+	}
+
+	code, index := formatCode([]rune(s))
+
+	p := parser.New(lexer.New(code))
+	p.ParseProgram()
+
+	// the parser does not recover, the errors after the first one follow from it
+	errs := p.ErrorDetails()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	i := len([]rune(s))
+	if errs[0].Pos.Index < len(index) {
+		i = index[errs[0].Pos.Index]
+	}
+
+	line, column := runePosition(s, i)
+
+	// the literal of an illegal token is a single byte of the input
+	return []*ExpressionError{{Line: line, Column: column, Message: strings.ToValidUTF8(errs[0].Message, "?")}}
+}
+
+// Escape sequences of the format string (the rune after the backslash)
+var formatEscapes = map[rune]string{'n': "\n", 't': "\t", '$': "$", '\\': "\\"}
+
+// The code as the worker parses it after the substitutions: escape sequences are
+// replaced and every substitution is replaced with the placeholder. index maps every
+// byte of the code to the index of the rune of the source it comes from.
+// The source is expected to be a valid format string.
+func formatCode(runes []rune) (string, []int) {
+	var b strings.Builder
+	index := []int{}
+	write := func(s string, from int) {
+		b.WriteString(s)
+		for range len(s) {
+			index = append(index, from)
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes):
+			write(formatEscapes[runes[i+1]], i)
+			i++
+		case runes[i] == '$' && i+1 < len(runes) && runes[i+1] == '{':
+			end := slices.Index(runes[i+2:], '}')
+			if end < 0 {
+				return b.String(), index
+			}
+
+			write(substitutionPlaceholder, i)
+			i += 2 + end
+		default:
+			write(string(runes[i]), i)
+		}
+	}
+
+	return b.String(), index
+}
+
+// Index of the rune that starts the invalid escape sequence or substitution, -1 if the
+// string is valid. The string is scanned the same way format.CheckFormatString does.
+func formatErrorIndex(runes []rune) int {
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 >= len(runes) || !strings.ContainsRune(`nt$\`, runes[i+1]) {
+				return i
+			}
+			i++
+		case '$':
+			if i+1 >= len(runes) || runes[i+1] != '{' {
+				return i
+			}
+
+			end := slices.Index(runes[i+2:], '}')
+			if end < 0 || context.CheckPath(strings.TrimSpace(string(runes[i+2:i+2+end]))) != nil {
+				return i
+			}
+			i += 2 + end
+		}
+	}
+
+	return -1
+}
+
+// Line and column (from 1) of the rune with the index
+func runePosition(s string, index int) (int, int) {
+	line, column := 1, 1
+	for i, r := range []rune(s) {
+		if i == index {
+			break
+		}
+
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return line, column
+}
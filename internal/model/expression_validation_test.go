@@ -0,0 +1,97 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunePosition(t *testing.T) {
+	tests := []struct {
+		s      string
+		index  int
+		line   int
+		column int
+	}{
+		{"abc", 0, 1, 1},
+		{"abc", 2, 1, 3},
+		{"ab\ncd", 3, 2, 1},
+		{"ab\ncd", 4, 2, 2},
+		{"ab\n\ncd", 4, 3, 1},
+		// columns are counted in characters, not bytes
+		{"приве\n мир", 8, 2, 3},
+		// the end of the string
+		{"ab\nc", 4, 2, 2},
+	}
+
+	for _, tt := range tests {
+		line, column := runePosition(tt.s, tt.index)
+		if line != tt.line || column != tt.column {
+			t.Errorf("runePosition(%q, %d) = %d:%d, want %d:%d", tt.s, tt.index, line, column, tt.line, tt.column)
+		}
+	}
+}
+
+func TestCheckExpressionPosition(t *testing.T) {
+	tests := []struct {
+		syntax string
+		expr   string
+		line   int
+		column int
+	}{
+		{SyntaxFormat, `Hello \q`, 1, 7},
+		{SyntaxFormat, `Hello \`, 1, 7},
+		{SyntaxFormat, "Hi\n$name", 2, 1},
+		{SyntaxFormat, "Hi ${name", 1, 4},
+		{SyntaxFormat, "a\nб ${user..name}", 2, 3},
+		{SyntaxCode, "x = 1\ny = ${a} + \\q", 2, 12},
+		{SyntaxCode, "x = \\$ + ${", 1, 10},
+		{SyntaxCode, "x = 1\ny = +\n", 2, 5},
+		{SyntaxCode, "x = ${a} +* 2", 1, 11},
+		{SyntaxCode, "s = \"${name}\"\nx = (1 + 2", 2, 10},
+		{SyntaxCode, "\\$ = 1", 1, 1},
+		{SyntaxCode, "приве = 1", 1, 1},
+	}
+
+	for _, tt := range tests {
+		errs := CheckExpression(tt.syntax, tt.expr)
+		if len(errs) != 1 {
+			t.Errorf("CheckExpression(%q, %q) = %d errors, want 1", tt.syntax, tt.expr, len(errs))
+			continue
+		}
+
+		if errs[0].Line != tt.line || errs[0].Column != tt.column {
+			t.Errorf("CheckExpression(%q, %q) error at %d:%d, want %d:%d",
+				tt.syntax, tt.expr, errs[0].Line, errs[0].Column, tt.line, tt.column)
+		}
+	}
+}
+
+func TestCheckExpressionValid(t *testing.T) {
+	tests := []struct {
+		syntax string
+		expr   string
+	}{
+		{SyntaxFormat, `Hello, ${user.name}!\n\t\$\\`},
+		{SyntaxPath, "user.items[0]"},
+		{SyntaxPath, " true "},
+		// the substitutions are replaced before the code is parsed
+		{SyntaxCode, "x = ${a} + 2\ns = \"${user.name}\""},
+		{SyntaxCode, "if (x > 1) { \"a\\tb\" } else { [1, 2][0] }"},
+		// the code is parsed, never executed
+		{SyntaxCode, "f = fn(n) { f(n + 1) }\nf(0)"},
+	}
+
+	for _, tt := range tests {
+		if errs := CheckExpression(tt.syntax, tt.expr); len(errs) != 0 {
+			t.Errorf("CheckExpression(%q, %q) = %v, want no errors", tt.syntax, tt.expr, errs[0].Message)
+		}
+	}
+}
+
+func TestCheckExpressionCodeTooLong(t *testing.T) {
+	// deep nesting is not parsed
+	errs := CheckExpression(SyntaxCode, strings.Repeat("(", maxCodeLen+1))
+	if len(errs) != 1 || errs[0].Line != 0 {
+		t.Errorf("CheckExpression() of the long code = %v, want the length error", errs)
+	}
+}
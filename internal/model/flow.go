@@ -0,0 +1,56 @@
+package model
+
+import "github.com/botscubes/bot-components/components"
+
+// Portable bot flow document.
+// Ids of groups and components are local to the document,
+// they are only used to link components with each other.
+
+const (
+	FlowFormat  = "botscubes.flow"
+	FlowVersion = 1
+)
+
+type Flow struct {
+	Format  string       `json:"format"`
+	Version int          `json:"version"`
+	Title   *string      `json:"title"`
+	Groups  []*FlowGroup `json:"groups"`
+}
+
+type FlowGroup struct {
+	Id               int64        `json:"id"`
+	Name             string       `json:"name"`
+	EntryComponentId *int64       `json:"entryComponentId,omitempty"`
+	Components       []*Component `json:"components"`
+}
+
+// Get the start component and its group
+func (f *Flow) Start() (*FlowGroup, *Component) {
+	for _, g := range f.Groups {
+		for _, c := range g.Components {
+			if c.Type == components.TypeStart {
+				return g, c
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// Get the component of the group, nil if there is no such component
+func (f *Flow) Component(groupId int64, componentId int64) *Component {
+	for _, g := range f.Groups {
+		if g.Id != groupId {
+			continue
+		}
+
+		for _, c := range g.Components {
+			if c.Id == componentId {
+				return c
+			}
+		}
+	}
+
+	return nil
+}
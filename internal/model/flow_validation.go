@@ -0,0 +1,148 @@
+package model
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/botscubes/bot-components/components"
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+// Add component id to the error message
+func componentError(id int64, err *se.ServiceError) *se.ServiceError {
+	return se.New(err.Code, "component "+strconv.FormatInt(id, 10)+": "+err.Message)
+}
+
+// Validation of the flow document: format, title, groups and every component
+// with its data, outputs and connection points.
+func (f *Flow) Validate() *se.ServiceError {
+	if f.Format != FlowFormat || f.Version != FlowVersion {
+		return e.ErrUnsupportedFlowFormat
+	}
+
+	if err := (&NewBotReq{Title: f.Title}).Validate(); err != nil {
+		return err
+	}
+
+	if len(f.Groups) == 0 {
+		return e.MissingParam("groups")
+	}
+
+	// component id -> group id
+	componentGroups := make(map[int64]int64)
+	comps := make(map[int64]*Component)
+	groupIds := make(map[int64]bool)
+	startCount := 0
+
+	for _, g := range f.Groups {
+		if g == nil {
+			return e.InvalidParam("groups")
+		}
+
+		if groupIds[g.Id] {
+			return e.InvalidParam("duplicate group id: " + strconv.FormatInt(g.Id, 10))
+		}
+		groupIds[g.Id] = true
+
+		if err := groupNameValidate(&g.Name); err != nil {
+			return err
+		}
+
+		for _, c := range g.Components {
+			if c == nil {
+				return e.InvalidParam("components")
+			}
+
+			if _, ok := comps[c.Id]; ok {
+				return e.InvalidParam("duplicate component id: " + strconv.FormatInt(c.Id, 10))
+			}
+
+			if c.Type == components.TypeStart {
+				startCount++
+			} else if !isAddableComponentType(c.Type) {
+				return componentError(c.Id, e.ErrUnknownComponent)
+			}
+
+			comps[c.Id] = c
+			componentGroups[c.Id] = g.Id
+		}
+	}
+
+	if startCount != 1 {
+		return e.InvalidParam("the flow must contain exactly one start component")
+	}
+
+	for _, g := range f.Groups {
+		if g.EntryComponentId == nil {
+			continue
+		}
+
+		if groupId, ok := componentGroups[*g.EntryComponentId]; !ok || groupId != g.Id {
+			return e.InvalidParam("group " + strconv.FormatInt(g.Id, 10) + ": entry component not found")
+		}
+	}
+
+	for id, c := range comps {
+		if err := c.validateInFlow(componentGroups[id], comps, componentGroups); err != nil {
+			return componentError(id, err)
+		}
+
+		if subflowGroupId := c.SubflowGroupId(); subflowGroupId != 0 && !groupIds[subflowGroupId] {
+			return componentError(id, e.ErrGroupNotFound)
+		}
+	}
+
+	return nil
+}
+
+func (c *Component) validateInFlow(groupId int64, comps map[int64]*Component, componentGroups map[int64]int64) *se.ServiceError {
+	if err := c.Position.Validate(); err != nil {
+		return err
+	}
+
+	if errs := ValidateComponentData(c.Type, c.Data, false); len(errs) > 0 {
+		return e.InvalidParam("data" + errs[0].Pointer + ": " + errs[0].Message)
+	}
+
+	info := GetComponentTypeInfo(c.Type)
+	if info == nil && len(c.Outputs) > 0 {
+		return e.ErrValidation
+	}
+
+	for name, targetId := range c.Outputs {
+		if err := info.ValidateOutput(name); err != nil {
+			return err
+		}
+
+		if info.NumericOutputs && !slices.Contains(info.Outputs, name) && !HasButton(c.Data, name) {
+			return e.InvalidParam("outputs." + name + ": button not found")
+		}
+
+		// connections never cross group boundaries
+		if g, ok := componentGroups[targetId]; !ok || g != groupId {
+			return e.InvalidParam("outputs." + name + ": target component not found")
+		}
+	}
+
+	for key, p := range c.ConnectionPoints {
+		if p == nil || p.SourceComponentId == nil || p.SourcePointName == nil {
+			return e.InvalidParam("connectionPoints." + key)
+		}
+
+		if key != ConnectionPointKey(*p.SourceComponentId, *p.SourcePointName) {
+			return e.InvalidParam("connectionPoints." + key + ": key does not match the source")
+		}
+
+		source, ok := comps[*p.SourceComponentId]
+		if !ok || componentGroups[*p.SourceComponentId] != groupId {
+			return e.InvalidParam("connectionPoints." + key + ": source component not found")
+		}
+
+		if targetId, ok := source.Outputs[*p.SourcePointName]; !ok || targetId != c.Id {
+			return e.InvalidParam("connectionPoints." + key + ": the source output does not lead to the component")
+		}
+	}
+
+	return nil
+}
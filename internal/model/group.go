@@ -0,0 +1,16 @@
+package model
+
+type Group struct {
+	Id               int64  `json:"id"`
+	Name             string `json:"name"`
+	EntryComponentId *int64 `json:"entryComponentId"`
+}
+
+type AddGroupReq struct {
+	Name *string `json:"name"`
+}
+
+type UpdGroupReq struct {
+	Name             *string `json:"name"`
+	EntryComponentId *int64  `json:"entryComponentId"`
+}
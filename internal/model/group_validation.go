@@ -0,0 +1,46 @@
+package model
+
+import (
+	"unicode/utf8"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+const (
+	MaxGroupNameLen = 50 // Max group name length
+)
+
+func groupNameValidate(name *string) *se.ServiceError {
+	if name == nil || *name == "" {
+		return e.MissingParam("name")
+	}
+
+	if utf8.RuneCountInString(*name) > MaxGroupNameLen {
+		return e.ErrGroupNameTooLong
+	}
+
+	return nil
+}
+
+func (r *AddGroupReq) Validate() *se.ServiceError {
+	return groupNameValidate(r.Name)
+}
+
+func (r *UpdGroupReq) Validate() *se.ServiceError {
+	if r.Name == nil && r.EntryComponentId == nil {
+		return e.ErrBadRequest
+	}
+
+	if r.Name != nil {
+		if err := groupNameValidate(r.Name); err != nil {
+			return err
+		}
+	}
+
+	if r.EntryComponentId != nil && *r.EntryComponentId < 1 {
+		return e.InvalidParam("entryComponentId")
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"bytes"
+
+	"github.com/goccy/go-json"
+)
+
+// State of the component at some moment, Component is nil if the component does not exist
+type ComponentState struct {
+	Id        int64      `json:"id"`
+	GroupId   int64      `json:"groupId"`
+	Component *Component `json:"component"`
+	// The component is the entry of its group
+	Entry bool `json:"entry,omitempty"`
+}
+
+// Check that the states of the component are the same. The breakpoint and the entry of
+// the group are changed without the journal and are not compared.
+func (s *ComponentState) Matches(other *ComponentState) bool {
+	if s.Id != other.Id || (s.Component == nil) != (other.Component == nil) {
+		return false
+	}
+
+	if s.Component == nil {
+		return true
+	}
+
+	if s.GroupId != other.GroupId {
+		return false
+	}
+
+	// the states are compared as json: a state from redis differs from a state
+	// from the database in the fields that are not encoded
+	a, errA := json.Marshal(s.Component.withoutBreakpoint())
+	b, errB := json.Marshal(other.Component.withoutBreakpoint())
+
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// Keep the states of the components that were changed, before and after are the states
+// of the same components in the same order
+func ChangedStates(before []*ComponentState, after []*ComponentState) ([]*ComponentState, []*ComponentState) {
+	changedBefore := []*ComponentState{}
+	changedAfter := []*ComponentState{}
+	for i, s := range before {
+		if s.Matches(after[i]) && s.Entry == after[i].Entry {
+			continue
+		}
+
+		changedBefore = append(changedBefore, s)
+		changedAfter = append(changedAfter, after[i])
+	}
+
+	return changedBefore, changedAfter
+}
+
+// Editor operation with the states of all affected components before and after it
+type JournalEntry struct {
+	Operation string            `json:"operation"`
+	Before    []*ComponentState `json:"before"`
+	After     []*ComponentState `json:"after"`
+}
+
+// Encode journal entry struct to binary format (for redis)
+func (j *JournalEntry) MarshalBinary() ([]byte, error) {
+	return json.Marshal(j)
+}
+
+// Decode journal entry from binary format to struct (for redis)
+func (j *JournalEntry) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &j)
+}
+
+func (c *Component) withoutBreakpoint() *Component {
+	copied := *c
+	copied.Breakpoint = false
+	return &copied
+}
+
+// Ids of the components linked with the component by outputs and connection points
+func (c *Component) LinkedIds() []int64 {
+	ids := []int64{}
+	for _, targetId := range c.Outputs {
+		ids = append(ids, targetId)
+	}
+
+	for _, p := range c.ConnectionPoints {
+		if p != nil && p.SourceComponentId != nil {
+			ids = append(ids, *p.SourceComponentId)
+		}
+	}
+
+	return ids
+}
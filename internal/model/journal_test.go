@@ -0,0 +1,72 @@
+package model
+
+import "testing"
+
+func TestComponentStateMatches(t *testing.T) {
+	state := func(modify func(c *Component)) *ComponentState {
+		c := &Component{
+			Id:       2,
+			Position: &Point{X: 1, Y: 2, Valid: true},
+			Outputs:  map[string]int64{"nextComponentId": 3},
+			Data:     map[string]any{"text": "Hi", "n": float64(1)},
+		}
+		modify(c)
+		return &ComponentState{Id: 2, GroupId: 1, Component: c}
+	}
+
+	tests := []struct {
+		name  string
+		a, b  *ComponentState
+		match bool
+	}{
+		{"same", state(func(*Component) {}), state(func(*Component) {}), true},
+		{"decoded position", state(func(*Component) {}), state(func(c *Component) { c.Position.Valid = false }), true},
+		{"breakpoint", state(func(*Component) {}), state(func(c *Component) { c.Breakpoint = true }), true},
+		{"data", state(func(*Component) {}), state(func(c *Component) { c.Data["text"] = "Bye" }), false},
+		{"outputs", state(func(*Component) {}), state(func(c *Component) { c.Outputs = nil }), false},
+		{"position", state(func(*Component) {}), state(func(c *Component) { c.Position.X = 5 }), false},
+		{"deleted", state(func(*Component) {}), &ComponentState{Id: 2}, false},
+		{"both deleted", &ComponentState{Id: 2}, &ComponentState{Id: 2, GroupId: 1}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Matches(tt.b); got != tt.match {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, got, tt.match)
+		}
+	}
+
+	entry := state(func(*Component) {})
+	entry.Entry = true
+	if !entry.Matches(state(func(*Component) {})) {
+		t.Errorf("entry: Matches() = false, want true")
+	}
+}
+
+func TestChangedStates(t *testing.T) {
+	state := func(id int64, text string, entry bool) *ComponentState {
+		return &ComponentState{
+			Id:        id,
+			GroupId:   1,
+			Component: &Component{Id: id, Data: map[string]any{"text": text}},
+			Entry:     entry,
+		}
+	}
+
+	before := []*ComponentState{state(2, "Hi", false), state(3, "Hi", false), state(4, "Hi", true), {Id: 5}}
+	after := []*ComponentState{state(2, "Hi", false), state(3, "Bye", false), state(4, "Hi", false), state(5, "Hi", false)}
+
+	changedBefore, changedAfter := ChangedStates(before, after)
+	if len(changedBefore) != 3 || len(changedAfter) != 3 {
+		t.Fatalf("ChangedStates() = %d, %d states, want 3, 3", len(changedBefore), len(changedAfter))
+	}
+
+	for i, id := range []int64{3, 4, 5} {
+		if changedBefore[i].Id != id || changedAfter[i].Id != id {
+			t.Errorf("ChangedStates()[%d] = %d, %d, want %d", i, changedBefore[i].Id, changedAfter[i].Id, id)
+		}
+	}
+
+	if changedBefore, _ = ChangedStates(before[:1], after[:1]); len(changedBefore) != 0 {
+		t.Errorf("ChangedStates() of the unchanged component = %d states, want 0", len(changedBefore))
+	}
+}
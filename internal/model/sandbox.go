@@ -0,0 +1,53 @@
+package model
+
+import "github.com/goccy/go-json"
+
+// Session of the test chat with the bot, every input is executed on the current draft flow
+type SandboxSession struct {
+	Id    string           `json:"id"`
+	State *SimulationState `json:"state"`
+	// Results of the http and code components by component id
+	Mocks map[int64]any `json:"mocks"`
+}
+
+// Encode sandbox session struct to binary format (for redis)
+func (s *SandboxSession) MarshalBinary() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// Decode sandbox session from binary format to struct (for redis)
+func (s *SandboxSession) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &s)
+}
+
+type NewSandboxSessionReq struct {
+	// Initial variables of the context
+	Variables map[string]any `json:"variables"`
+	// Results of the http and code components by component id, they are not executed
+	Mocks map[string]any `json:"mocks"`
+}
+
+type NewSandboxSessionRes struct {
+	SessionId string `json:"sessionId"`
+	Ticket    string `json:"ticket"`
+}
+
+// Single-use ticket of the websocket connection to the session, issued to the bot owner
+type SandboxTicket struct {
+	SessionId string `json:"sessionId"`
+	UserId    int64  `json:"userId"`
+}
+
+// Encode sandbox ticket struct to binary format (for redis)
+func (t *SandboxTicket) MarshalBinary() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+// Decode sandbox ticket from binary format to struct (for redis)
+func (t *SandboxTicket) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, &t)
+}
+
+type SandboxTicketRes struct {
+	Ticket string `json:"ticket"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import (
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+func (r *NewSandboxSessionReq) Validate() *se.ServiceError {
+	return validateMocks(r.Mocks)
+}
+
+// Mocks by component id, the request must be valid
+func (r *NewSandboxSessionReq) MockResults() map[int64]any {
+	return mockResults(r.Mocks)
+}
@@ -0,0 +1,26 @@
+package model
+
+// Input of the user in the simulated conversation: a message or a press of the button
+// (by index) of the buttons component the user is on.
+type SimulationInput struct {
+	Text   *string `json:"text"`
+	Button *int    `json:"button"`
+}
+
+type SimulateReq struct {
+	Inputs *[]*SimulationInput `json:"inputs"`
+	// Initial variables of the context
+	Variables map[string]any `json:"variables"`
+	// Results of the http and code components by component id, they are not executed
+	Mocks map[string]any `json:"mocks"`
+}
+
+// State of the simulated user, it is enough to continue the conversation
+type SimulationState struct {
+	// Component the user is on
+	ComponentId int64 `json:"componentId"`
+	// Sub-flow components to return to after the called group ends
+	Stack     []int64        `json:"stack"`
+	Variables map[string]any `json:"variables"`
+	Finished  bool           `json:"finished"`
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"strconv"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+const (
+	MaxSimulationInputs = 100
+)
+
+func (i *SimulationInput) Validate() *se.ServiceError {
+	if (i.Text == nil) == (i.Button == nil) {
+		return e.InvalidParam("inputs: either text or button must be specified")
+	}
+
+	if i.Button != nil && *i.Button < 0 {
+		return e.InvalidParam("inputs: button must not be negative")
+	}
+
+	return nil
+}
+
+func (r *SimulateReq) Validate() *se.ServiceError {
+	if r.Inputs == nil {
+		return e.MissingParam("inputs")
+	}
+
+	if err := validateInputs(*r.Inputs); err != nil {
+		return err
+	}
+
+	return validateMocks(r.Mocks)
+}
+
+func validateInputs(inputs []*SimulationInput) *se.ServiceError {
+	if len(inputs) > MaxSimulationInputs {
+		return e.InvalidParam("inputs: no more than " + strconv.Itoa(MaxSimulationInputs) + " inputs")
+	}
+
+	for _, i := range inputs {
+		if i == nil {
+			return e.InvalidParam("inputs")
+		}
+
+		if err := i.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateMocks(mocks map[string]any) *se.ServiceError {
+	for key := range mocks {
+		if _, err := strconv.ParseInt(key, 10, 64); err != nil {
+			return e.InvalidParam("mocks: " + key)
+		}
+	}
+
+	return nil
+}
+
+// Mocks by component id, the request must be valid
+func (r *SimulateReq) MockResults() map[int64]any {
+	return mockResults(r.Mocks)
+}
+
+func mockResults(m map[string]any) map[int64]any {
+	mocks := make(map[int64]any, len(m))
+	for key, v := range m {
+		id, _ := strconv.ParseInt(key, 10, 64)
+		mocks[id] = v
+	}
+
+	return mocks
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"slices"
 	"strconv"
 
 	e "github.com/botscubes/bot-service/internal/api/errors"
@@ -21,247 +22,40 @@ func validateId(data any) error {
 	return nil
 }
 
-func ValidateSpecificComponentData(ctype string, data map[string]any) *se.ServiceError {
-	for key, value := range data {
-		validate, ok := SpecificComponentDataValidation[ctype][key]
-		if !ok {
-			return e.NonExistentParam(key)
+// Required data keys that are missing or empty
+func MissingComponentData(ctype string, data map[string]any) []string {
+	missing := []string{}
+	info := GetComponentTypeInfo(ctype)
+	if info == nil {
+		return missing
+	}
+
+	for _, f := range info.Data {
+		if !f.Required {
+			continue
 		}
-		se := validate(value)
 
-		if se != nil {
-			return se
+		value, ok := data[f.Name]
+		if !ok || value == nil || value == "" {
+			missing = append(missing, f.Name)
 		}
 	}
-	return nil
-}
-
-var SpecificComponentDataValidation = map[string]map[string]func(data any) *se.ServiceError{
-	"condition": {
-		"expression": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("expression")
-			}
 
-			return nil
-		},
-	},
-	"message": {
-		"text": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("text")
-			}
-			return nil
-		},
-	},
-	"format": {
-		"formatString": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("formatString")
-			}
-			return nil
-		},
-	},
-	"buttons": {
-		"text": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("text")
-			}
-			return nil
-		},
-		"buttons": func(data any) *se.ServiceError {
-			return nil
-		},
-	},
-	"code": {
-		"code": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("code")
-			}
-			return nil
-		},
-	},
-	"toInt": {
-		"source": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("source")
-			}
-			return nil
-		},
-	},
-	"move": {
-		"source": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("source")
-			}
-			return nil
-		},
-	},
-	"http": {
-		"url": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("url")
-			}
-			return nil
-		},
-		"method": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("method")
-			}
-			return nil
-		},
-		"body": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("body")
-			}
-			return nil
-		},
-		"header": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("header")
-			}
-			return nil
-		},
-	},
-	"photo": {
-		"name": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("name")
-			}
-			return nil
-		},
-	},
-	"fromJSON": {
-		"json": func(data any) *se.ServiceError {
-			_, ok := data.(string)
-			if !ok {
-				return e.InvalidParam("json")
-			}
-			return nil
-		},
-	},
+	return missing
 }
 
-func checkKeyInMap(m map[string]bool, k string) *se.ServiceError {
-	_, ok := m[k]
-	if !ok {
-		return e.NoOutputPointName(k)
+// Check that the component of the type has the output
+func (t *ComponentTypeInfo) ValidateOutput(outputName string) *se.ServiceError {
+	if slices.Contains(t.Outputs, outputName) {
+		return nil
 	}
-	return nil
-}
 
-var SpecificComponentOutputValidation = map[string]func(outputName string) *se.ServiceError{
-	"start": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"nextComponentId": true,
-		}
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"condition": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-			"idIfFalse":       true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"message": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"textInput": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"format": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"code": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"toInt": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"move": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"http": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"fromJSON": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"photo": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError":       true,
-			"nextComponentId": true,
-		}
-
-		return checkKeyInMap(outputNames, outputName)
-	},
-	"buttons": func(outputName string) *se.ServiceError {
-		outputNames := map[string]bool{
-			"idIfError": true,
-		}
-		err := checkKeyInMap(outputNames, outputName)
-		if err == nil {
-			return nil
-		}
+	if !t.NumericOutputs {
+		return e.NoOutputPointName(outputName)
+	}
 
-		if _, err := strconv.Atoi(outputName); err != nil {
-			return e.OutputPointNameIsNotNumber(outputName)
-		}
-		return nil
-	},
+	if _, err := strconv.Atoi(outputName); err != nil {
+		return e.OutputPointNameIsNotNumber(outputName)
+	}
+	return nil
 }
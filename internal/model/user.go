@@ -15,7 +15,7 @@ type User struct {
 	LastName  *string `json:"lastName"`
 	Username  *string `json:"username"`
 	StepID
-	Status UserStatus `json:"-"`
+	Status UserStatus `json:"status"`
 }
 
 type StepID struct {
@@ -31,3 +31,34 @@ func (c *User) MarshalBinary() ([]byte, error) {
 func (c *User) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, &c)
 }
+
+// Query of the users list
+type GetUsersReq struct {
+	// Part of the username or the Telegram id
+	Search string      `query:"search"`
+	Status *UserStatus `query:"status"`
+	Limit  int         `query:"limit"`
+	Offset int         `query:"offset"`
+}
+
+type GetUsersRes struct {
+	Users []*User `json:"users"`
+	// Number of the users matching the query
+	Total int64 `json:"total"`
+}
+
+// Component to put the user on
+type SetUserStepReq struct {
+	GroupId     *int64 `json:"groupId"`
+	ComponentId *int64 `json:"componentId"`
+}
+
+// Reset of all users on the component to the start component
+type ResetUsersReq struct {
+	ComponentId *int64 `json:"componentId"`
+}
+
+type ResetUsersRes struct {
+	// Number of the reset users
+	Count int64 `json:"count"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strconv"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+const (
+	DefaultUsersLimit = 20
+	MaxUsersLimit     = 100
+	maxUserSearchLen  = 64
+)
+
+// Validate the query, the limit is set to the default if it is not specified
+func (r *GetUsersReq) Validate() *se.ServiceError {
+	if len([]rune(r.Search)) > maxUserSearchLen {
+		return e.InvalidParam("search: no more than " + strconv.Itoa(maxUserSearchLen) + " characters")
+	}
+
+	if r.Status != nil && *r.Status < 0 {
+		return e.InvalidParam("status")
+	}
+
+	if r.Limit == 0 {
+		r.Limit = DefaultUsersLimit
+	}
+
+	if r.Limit < 0 || r.Limit > MaxUsersLimit {
+		return e.InvalidParam("limit: from 1 to " + strconv.Itoa(MaxUsersLimit))
+	}
+
+	if r.Offset < 0 {
+		return e.InvalidParam("offset")
+	}
+
+	return nil
+}
+
+func (r *SetUserStepReq) Validate() *se.ServiceError {
+	if r.GroupId == nil {
+		return e.MissingParam("groupId")
+	}
+
+	if r.ComponentId == nil {
+		return e.MissingParam("componentId")
+	}
+
+	return nil
+}
+
+func (r *ResetUsersReq) Validate() *se.ServiceError {
+	if r.ComponentId == nil {
+		return e.MissingParam("componentId")
+	}
+
+	if *r.ComponentId < 1 {
+		return e.InvalidParam("componentId")
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/botscubes/bot-components/context"
+)
+
+// Reference of the component to a context variable
+type VariableRef struct {
+	// "data.<field>" or "path"
+	Field string `json:"field"`
+	// Name of the variable (the first part of the path)
+	Name string `json:"name"`
+}
+
+// Variables read by the component, in the order of the registry fields.
+// The code of the code component is not analyzed.
+func (c *Component) VariableReads() []*VariableRef {
+	refs := []*VariableRef{}
+	info := GetComponentTypeInfo(c.Type)
+	if info == nil {
+		return refs
+	}
+
+	for _, f := range info.Data {
+		expr, _ := c.Data[f.Name].(string)
+		if expr == "" {
+			continue
+		}
+
+		var names []string
+		switch f.Syntax {
+		case SyntaxPath:
+			if expr = strings.TrimSpace(expr); expr != "true" && expr != "false" {
+				names = pathVariables(expr)
+			}
+		case SyntaxFormat:
+			names = formatVariables(expr)
+		}
+		// the code reads the variables by bare identifiers that cannot be told apart
+		// from the variables of the code itself, so the code is not analyzed
+
+		for _, name := range names {
+			ref := &VariableRef{Field: "data." + f.Name, Name: name}
+			if !slices.ContainsFunc(refs, func(r *VariableRef) bool { return *r == *ref }) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	if info.PathUsage == PathSource && c.Path != "" {
+		for _, name := range pathVariables(c.Path) {
+			refs = append(refs, &VariableRef{Field: "path", Name: name})
+		}
+	}
+
+	return refs
+}
+
+// Variable written by the component, empty if the component writes nothing
+func (c *Component) VariableWrite() string {
+	info := GetComponentTypeInfo(c.Type)
+	if info == nil || info.PathUsage != PathResult || c.Path == "" {
+		return ""
+	}
+
+	it := context.NewPathUnitIterator(strings.TrimSpace(c.Path))
+	unit, err := it.Next()
+	if err != nil || unit == nil || unit.Type != context.Object {
+		return ""
+	}
+
+	return unit.Propery
+}
+
+// Variables of the path: the variable of the path and the variables of its indexes (a[i])
+func pathVariables(path string) []string {
+	return unitVariables(context.NewPathUnitIterator(path))
+}
+
+func unitVariables(it *context.PathUnitIterator) []string {
+	names := []string{}
+	for first := true; it.HasNext(); first = false {
+		unit, err := it.Next()
+		if err != nil || unit == nil {
+			break
+		}
+
+		if first && unit.Type == context.Object {
+			names = append(names, unit.Propery)
+		}
+
+		if unit.Subpath != nil {
+			names = append(names, unitVariables(unit.Subpath)...)
+		}
+	}
+
+	return names
+}
+
+// Variables of the ${...} substitutions of the format string
+func formatVariables(s string) []string {
+	names := []string{}
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\':
+			// escape sequence
+			i++
+		case runes[i] == '$' && i+1 < len(runes) && runes[i+1] == '{':
+			end := slices.Index(runes[i+2:], '}')
+			if end < 0 {
+				return names
+			}
+
+			path := strings.TrimSpace(string(runes[i+2 : i+2+end]))
+			names = append(names, pathVariables(path)...)
+			i += 2 + end
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/botscubes/bot-components/components"
+)
+
+func readNames(c *Component) []string {
+	names := []string{}
+	for _, r := range c.VariableReads() {
+		names = append(names, r.Field+":"+r.Name)
+	}
+
+	return names
+}
+
+func TestVariableReads(t *testing.T) {
+	tests := []struct {
+		ctype components.ComponentType
+		data  map[string]any
+		path  string
+		want  []string
+	}{
+		{components.TypeMessage, map[string]any{"text": "Hi, ${user.name} \\${x}"}, "", []string{"data.text:user"}},
+		{components.TypeToInt, map[string]any{"source": "items[i]"}, "n", []string{"data.source:items", "data.source:i"}},
+		{components.TypeCondition, map[string]any{"expression": "true"}, "", []string{}},
+		{components.TypePhoto, map[string]any{"name": "${file}"}, "photos", []string{"data.name:file", "path:photos"}},
+		// the code reads the variables by bare identifiers, it is not analyzed
+		{components.TypeCode, map[string]any{"code": "x = a + ${b}"}, "result", []string{}},
+	}
+
+	for _, tt := range tests {
+		c := &Component{Data: tt.data}
+		c.Type = tt.ctype
+		c.Path = tt.path
+
+		if got := readNames(c); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: VariableReads() = %v, want %v", tt.ctype, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Published immutable snapshot of the bot flow
+type Version struct {
+	Number    int64     `json:"number"`
+	UserId    int64     `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+	Message   *string   `json:"message"`
+	Flow      *Flow     `json:"flow,omitempty"`
+}
+
+type PublishReq struct {
+	Message *string `json:"message"`
+}
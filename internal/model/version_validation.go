@@ -0,0 +1,20 @@
+package model
+
+import (
+	"unicode/utf8"
+
+	e "github.com/botscubes/bot-service/internal/api/errors"
+	se "github.com/botscubes/user-service/pkg/service_error"
+)
+
+const (
+	MaxVersionMessageLen = 255 // Max version message length
+)
+
+func (r *PublishReq) Validate() *se.ServiceError {
+	if r.Message != nil && utf8.RuneCountInString(*r.Message) > MaxVersionMessageLen {
+		return e.ErrVersionMessageTooLong
+	}
+
+	return nil
+}
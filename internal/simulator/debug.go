@@ -0,0 +1,209 @@
+package simulator
+
+import (
+	"sync"
+
+	"github.com/botscubes/bot-service/internal/model"
+)
+
+// Step-by-step debugging of the flow. The run is paused before the components with
+// breakpoints, the inputs of the user are taken from the queue when the flow waits
+// for an input.
+
+type DebugStatus string
+
+const (
+	// The run is paused before the component
+	StatusPaused DebugStatus = "paused"
+	// The flow waits for an input and the queue of the inputs is empty
+	StatusWaiting  DebugStatus = "waiting"
+	StatusFinished DebugStatus = "finished"
+)
+
+// State of the debugger after the command
+type DebugState struct {
+	Status DebugStatus `json:"status"`
+	// Component the run is paused before or the user is on
+	ComponentId int64 `json:"componentId"`
+	// Sub-flow components to return to after the called group ends
+	Stack     []int64        `json:"stack"`
+	Variables map[string]any `json:"variables"`
+	// Messages and executed components of the last command
+	Transcript []*Message `json:"transcript"`
+	Visited    []int64    `json:"visited"`
+	// Number of the queued inputs
+	Inputs int `json:"inputs"`
+}
+
+type Debugger struct {
+	mu sync.Mutex
+
+	flow        *model.Flow
+	sim         *Simulator
+	inputs      []*model.SimulationInput
+	breakpoints map[int64]bool
+	paused      bool
+
+	// transcript and visited components before the last command
+	transcriptFrom int
+	visitedFrom    int
+}
+
+// New debugger of the flow, the user is on the start component and waits for an input
+func NewDebugger(f *model.Flow, mocks map[int64]any) *Debugger {
+	return &Debugger{
+		flow:        f,
+		sim:         New(f, mocks),
+		inputs:      []*model.SimulationInput{},
+		breakpoints: make(map[int64]bool),
+	}
+}
+
+// Debugger of the saved session, the session goes on from the saved state
+func RestoreDebugger(s *model.DebugSession) (*Debugger, error) {
+	d := NewDebugger(s.Flow, s.Mocks)
+	if err := d.sim.Restore(s.State); err != nil {
+		return nil, err
+	}
+
+	if s.Inputs != nil {
+		d.inputs = s.Inputs
+	}
+	d.paused = s.Paused
+
+	return d, nil
+}
+
+// Session to save between the commands, the breakpoints are not saved
+func (d *Debugger) Session(id string) *model.DebugSession {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return &model.DebugSession{
+		Id:     id,
+		Flow:   d.flow,
+		State:  d.sim.State(),
+		Mocks:  d.sim.mocks,
+		Inputs: d.inputs,
+		Paused: d.paused,
+	}
+}
+
+// Set the initial variables of the context
+func (d *Debugger) SetVariables(variables map[string]any) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.sim.SetVariables(variables)
+}
+
+// Set the components to pause before, the breakpoints may change during the session
+func (d *Debugger) SetBreakpoints(ids []int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.breakpoints = make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		d.breakpoints[id] = true
+	}
+}
+
+// Add the inputs to the queue, false if the queue would exceed the max number of the inputs
+func (d *Debugger) AddInputs(inputs []*model.SimulationInput) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.inputs)+len(inputs) > model.MaxSimulationInputs {
+		return false
+	}
+
+	d.inputs = append(d.inputs, inputs...)
+	return true
+}
+
+// Run until a breakpoint, an input with the empty queue or the end of the flow
+func (d *Debugger) Continue() *DebugState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.begin()
+	for !d.sim.state.Finished {
+		if !d.paused {
+			if !d.deliver() {
+				break
+			}
+
+			// the component that receives the input
+			if d.breakpoints[d.sim.state.ComponentId] {
+				d.paused = true
+				break
+			}
+		}
+
+		if d.paused = d.sim.run(d.isBreakpoint); d.paused {
+			break
+		}
+	}
+
+	return d.state()
+}
+
+// Execute one component, the input from the queue is delivered if the flow waits for it
+func (d *Debugger) Step() *DebugState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.begin()
+	if !d.paused && !d.sim.state.Finished {
+		d.paused = d.deliver()
+	}
+
+	if d.paused {
+		d.paused = d.sim.step()
+	}
+
+	return d.state()
+}
+
+// Deliver the next input of the queue, false if the queue is empty
+func (d *Debugger) deliver() bool {
+	for len(d.inputs) > 0 {
+		input := d.inputs[0]
+		d.inputs = d.inputs[1:]
+
+		if d.sim.deliver(input) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *Debugger) isBreakpoint(componentId int64) bool {
+	return d.breakpoints[componentId]
+}
+
+func (d *Debugger) begin() {
+	d.transcriptFrom = len(d.sim.transcript)
+	d.visitedFrom = len(d.sim.visited)
+}
+
+func (d *Debugger) state() *DebugState {
+	status := StatusWaiting
+	if d.sim.state.Finished {
+		status = StatusFinished
+	} else if d.paused {
+		status = StatusPaused
+	}
+
+	return &DebugState{
+		Status:      status,
+		ComponentId: d.sim.state.ComponentId,
+		Stack:       append([]int64{}, d.sim.state.Stack...),
+		Variables:   d.sim.variables(),
+		// the next command appends to the slices of the simulator
+		Transcript: append([]*Message{}, d.sim.transcript[d.transcriptFrom:]...),
+		Visited:    append([]int64{}, d.sim.visited[d.visitedFrom:]...),
+		Inputs:     len(d.inputs),
+	}
+}
@@ -0,0 +1,76 @@
+package simulator
+
+import "github.com/botscubes/bot-components/io"
+
+const (
+	FromUser = "user"
+	FromBot  = "bot"
+)
+
+// Types of the messages of the transcript
+const (
+	TypeText    = "text"
+	TypeButtons = "buttons"
+	TypePhoto   = "photo"
+	TypeError   = "error"
+)
+
+type Message struct {
+	From string `json:"from"`
+	Type string `json:"type"`
+	// Component that sent the message of the bot
+	ComponentId int64    `json:"componentId,omitempty"`
+	Text        string   `json:"text"`
+	Buttons     []string `json:"buttons,omitempty"`
+}
+
+type Result struct {
+	Transcript []*Message     `json:"transcript"`
+	Variables  map[string]any `json:"variables"`
+	Visited    []int64        `json:"visited"`
+	// Number of the processed inputs
+	Inputs int `json:"inputs"`
+	// Component the user is on after the last input
+	ComponentId int64 `json:"componentId"`
+	// The flow has ended, the rest of the inputs are not processed
+	Finished bool `json:"finished"`
+}
+
+// Input/output of the simulated user, the messages of the bot are added to the transcript
+type simulationIO struct {
+	sim         *Simulator
+	input       *string
+	componentId int64
+}
+
+func (o *simulationIO) ReadText() *string {
+	return o.input
+}
+
+func (o *simulationIO) print(m *Message) {
+	m.From = FromBot
+	m.ComponentId = o.componentId
+	o.sim.transcript = append(o.sim.transcript, m)
+}
+
+func (o *simulationIO) PrintText(text string) error {
+	o.print(&Message{Type: TypeText, Text: text})
+	return nil
+}
+
+func (o *simulationIO) PrintButtons(text string, buttons []*io.ButtonData) error {
+	texts := make([]string, 0, len(buttons))
+	for _, b := range buttons {
+		if b != nil {
+			texts = append(texts, b.Text)
+		}
+	}
+
+	o.print(&Message{Type: TypeButtons, Text: text, Buttons: texts})
+	return nil
+}
+
+func (o *simulationIO) PrintPhoto(file []byte, name string) error {
+	o.print(&Message{Type: TypePhoto, Text: name})
+	return nil
+}
@@ -0,0 +1,396 @@
+package simulator
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/botscubes/bot-components/components"
+	"github.com/botscubes/bot-components/context"
+	"github.com/botscubes/bot-components/exec"
+	"github.com/botscubes/bot-service/internal/config"
+	"github.com/botscubes/bot-service/internal/model"
+	"github.com/goccy/go-json"
+)
+
+// Simulation of the bot flow without Telegram and the worker. Components are executed
+// by the executor of bot-components with a fake input/output, the results of http
+// requests and code are replaced by mocks. Every user input is delivered to the
+// component the user is on, then the flow goes on until an input component waits
+// for the next input.
+
+// Max number of components executed for one input (protection against loops)
+const maxSteps = 1000
+
+var (
+	ErrComponentNotFound = errors.New("Component not found")
+	ErrGroupHasNoEntry   = errors.New("The group has no entry component")
+	ErrStepLimit         = errors.New("The step limit is exceeded, the flow may contain a loop")
+	ErrNoMock            = errors.New("HTTP requests are not sent by the simulator, the mock of the result is missing")
+	ErrNoCodeMock        = errors.New("Code is not executed by the simulator, the mock of the result is missing")
+	ErrNotButtons        = errors.New("The user is not on a buttons component")
+	ErrButtonNotFound    = errors.New("Button not found")
+)
+
+type Simulator struct {
+	comps   map[int64]*model.Component
+	entries map[int64]*int64
+	mocks   map[int64]any
+
+	state *model.SimulationState
+	ctx   *context.Context
+	io    *simulationIO
+
+	transcript []*Message
+	visited    []int64
+	// number of the processed inputs
+	inputs int
+}
+
+// New simulator of the flow, the user is on the start component
+func New(f *model.Flow, mocks map[int64]any) *Simulator {
+	s := &Simulator{
+		comps:   make(map[int64]*model.Component),
+		entries: make(map[int64]*int64),
+		mocks:   mocks,
+		state: &model.SimulationState{
+			ComponentId: config.MainComponentId,
+			Stack:       []int64{},
+		},
+		ctx:        context.NewContext(),
+		transcript: []*Message{},
+		visited:    []int64{},
+	}
+	s.io = &simulationIO{sim: s}
+
+	for _, g := range f.Groups {
+		s.entries[g.Id] = g.EntryComponentId
+		for _, c := range g.Components {
+			s.comps[c.Id] = c
+		}
+	}
+
+	return s
+}
+
+// Initial state of the user on the start component with the variables of the context
+func NewState(variables map[string]any) (*model.SimulationState, error) {
+	s := New(&model.Flow{}, nil)
+	if err := s.SetVariables(variables); err != nil {
+		return nil, err
+	}
+
+	return s.State(), nil
+}
+
+// Continue the conversation from the state
+func (s *Simulator) Restore(state *model.SimulationState) error {
+	return s.setState(state)
+}
+
+// Set the initial variables of the context
+func (s *Simulator) SetVariables(variables map[string]any) error {
+	return s.setState(&model.SimulationState{
+		ComponentId: s.state.ComponentId,
+		Stack:       s.state.Stack,
+		Variables:   variables,
+		Finished:    s.state.Finished,
+	})
+}
+
+func (s *Simulator) setState(state *model.SimulationState) error {
+	data, err := json.Marshal(state.Variables)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.NewContext()
+	if state.Variables != nil {
+		if ctx, err = context.NewContextFromJSON(data); err != nil {
+			return err
+		}
+	}
+
+	s.ctx = ctx
+	s.state = state
+	if s.state.Stack == nil {
+		s.state.Stack = []int64{}
+	}
+
+	return nil
+}
+
+// Current state of the user
+func (s *Simulator) State() *model.SimulationState {
+	state := *s.state
+	state.Variables = s.variables()
+	return &state
+}
+
+func (s *Simulator) variables() map[string]any {
+	variables := map[string]any{}
+	data, err := s.ctx.ToJSON()
+	if err == nil {
+		_ = json.Unmarshal(data, &variables)
+	}
+
+	return variables
+}
+
+// Send the input of the user
+func (s *Simulator) Send(input *model.SimulationInput) {
+	if s.deliver(input) {
+		s.run(nil)
+	}
+}
+
+// Deliver the input to the component the user is on, false if it is not delivered
+func (s *Simulator) deliver(input *model.SimulationInput) bool {
+	if s.state.Finished {
+		return false
+	}
+	s.inputs++
+
+	text, err := s.inputText(input)
+	if err != nil {
+		s.transcript = append(s.transcript, &Message{From: FromUser, Type: TypeError, Text: err.Error()})
+		return false
+	}
+	s.transcript = append(s.transcript, &Message{From: FromUser, Type: TypeText, Text: text})
+
+	s.io.input = &text
+	return true
+}
+
+// Execute components until the flow waits for an input or ends. The run is paused
+// before the next component if pause returns true for it, true if the run is paused.
+func (s *Simulator) run(pause func(componentId int64) bool) bool {
+	for step := 0; ; step++ {
+		if step == maxSteps {
+			s.fail(s.state.ComponentId, ErrStepLimit)
+			s.state.Finished = true
+			return false
+		}
+
+		if !s.step() {
+			return false
+		}
+
+		if pause != nil && pause(s.state.ComponentId) {
+			return true
+		}
+	}
+}
+
+// Text of the input, the button is pressed by its text
+func (s *Simulator) inputText(input *model.SimulationInput) (string, error) {
+	if input.Text != nil {
+		return *input.Text, nil
+	}
+
+	c, ok := s.comps[s.state.ComponentId]
+	if !ok || c.Type != components.TypeButtons {
+		return "", ErrNotButtons
+	}
+
+	buttons, _ := c.Data["buttons"].(map[string]any)
+	button, _ := buttons[strconv.Itoa(*input.Button)].(map[string]any)
+	text, ok := button["text"].(string)
+	if !ok {
+		return "", ErrButtonNotFound
+	}
+
+	return text, nil
+}
+
+// Execute the component the user is on, false if the flow waits for an input or ended
+func (s *Simulator) step() bool {
+	id := s.state.ComponentId
+	c, ok := s.comps[id]
+	if !ok {
+		s.fail(id, ErrComponentNotFound)
+		s.state.Finished = true
+		return false
+	}
+
+	s.visited = append(s.visited, id)
+	s.io.componentId = id
+
+	next, err := s.execute(c)
+	// only the first component receives the input
+	s.io.input = nil
+
+	if err != nil {
+		s.fail(id, err)
+	}
+
+	// not connected output of the condition
+	if next != nil && *next == 0 {
+		next = nil
+	}
+
+	if next != nil && *next == id && isInput(c.Type) {
+		// waits for the input
+		return false
+	}
+
+	if next == nil {
+		if err != nil && isInput(c.Type) {
+			// the user can repeat the input
+			return false
+		}
+
+		if err == nil {
+			next = s.returnFromSubflow()
+		}
+	}
+
+	if next == nil {
+		s.state.Finished = true
+		return false
+	}
+
+	s.state.ComponentId = *next
+
+	return true
+}
+
+func (s *Simulator) execute(c *model.Component) (*int64, error) {
+	switch c.Type {
+	case model.TypeSubflow:
+		entryId := s.entries[c.SubflowGroupId()]
+		if entryId == nil {
+			return output(c, "idIfError"), ErrGroupHasNoEntry
+		}
+
+		s.state.Stack = append(s.state.Stack, c.Id)
+		return entryId, nil
+
+	case components.TypeHTTP, components.TypeCode:
+		// code is not executed in the process of the service: it has no time and
+		// memory limits, a loop or a deep recursion would hang or crash the service
+		result, ok := s.mocks[c.Id]
+		if !ok {
+			return output(c, "idIfError"), mockError(c.Type)
+		}
+
+		_ = s.ctx.SetValue(c.Path, &result)
+		return output(c, "nextComponentId"), nil
+	}
+
+	cmp, err := instance(c)
+	if err != nil {
+		return output(c, "idIfError"), err
+	}
+
+	return exec.NewExecutor(s.ctx, s.io).Execute(cmp)
+}
+
+func mockError(t components.ComponentType) error {
+	if t == components.TypeCode {
+		return ErrNoCodeMock
+	}
+
+	return ErrNoMock
+}
+
+// The called group ended, the flow goes on from the sub-flow component
+func (s *Simulator) returnFromSubflow() *int64 {
+	for len(s.state.Stack) > 0 {
+		callerId := s.state.Stack[len(s.state.Stack)-1]
+		s.state.Stack = s.state.Stack[:len(s.state.Stack)-1]
+
+		if next := output(s.comps[callerId], "nextComponentId"); next != nil {
+			return next
+		}
+	}
+
+	return nil
+}
+
+func (s *Simulator) fail(componentId int64, err error) {
+	s.transcript = append(s.transcript, &Message{
+		From:        FromBot,
+		Type:        TypeError,
+		ComponentId: componentId,
+		Text:        err.Error(),
+	})
+}
+
+// Result of the simulation
+func (s *Simulator) Result() *Result {
+	return &Result{
+		Transcript:  s.transcript,
+		Variables:   s.variables(),
+		Visited:     s.visited,
+		Inputs:      s.inputs,
+		ComponentId: s.state.ComponentId,
+		Finished:    s.state.Finished,
+	}
+}
+
+func output(c *model.Component, name string) *int64 {
+	if c == nil {
+		return nil
+	}
+
+	if id, ok := c.Outputs[name]; ok {
+		return &id
+	}
+
+	return nil
+}
+
+func isInput(t components.ComponentType) bool {
+	return t == components.TypeTextInput || t == components.TypeButtons
+}
+
+// Component of bot-components with the data of the component, code is never instantiated
+func instance(c *model.Component) (components.Component, error) {
+	var cmp components.Component
+	switch c.Type {
+	case components.TypeStart:
+		cmp = &components.StartComponent{}
+	case components.TypeFormat:
+		cmp = &components.FormatComponent{}
+	case components.TypeCondition:
+		cmp = &components.ConditionComponent{}
+	case components.TypeMessage:
+		cmp = &components.MessageComponent{}
+	case components.TypeTextInput:
+		cmp = &components.TextInputComponent{}
+	case components.TypeButtons:
+		cmp = &components.ButtonComponent{}
+	case components.TypeToInt:
+		cmp = &components.ToIntComponent{}
+	case components.TypeMove:
+		cmp = &components.MoveComponent{}
+	case components.TypeFromJSON:
+		cmp = &components.FromJSONComponent{}
+	case components.TypePhoto:
+		cmp = &components.PhotoComponent{}
+	default:
+		return nil, errors.New("Unknown component type: " + c.Type)
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"id":      c.Id,
+		"type":    c.Type,
+		"path":    c.Path,
+		"data":    c.Data,
+		"outputs": c.Outputs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cmp); err != nil {
+		return nil, err
+	}
+
+	// the buttons component writes to its outputs
+	if b, ok := cmp.(*components.ButtonComponent); ok && b.Outputs == nil {
+		b.Outputs = make(map[string]*int64)
+	}
+
+	return cmp, nil
+}